@@ -0,0 +1,8 @@
+package assets
+
+import "embed"
+
+// FS holds the web app served by the /app static route.
+//
+//go:embed public
+var FS embed.FS
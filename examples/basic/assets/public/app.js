@@ -0,0 +1,10 @@
+fetch("/api/v1/pet")
+  .then((response) => response.json())
+  .then((pets) => {
+    const list = document.getElementById("pets");
+    for (const pet of pets) {
+      const item = document.createElement("li");
+      item.textContent = pet.name;
+      list.appendChild(item);
+    }
+  });
@@ -0,0 +1,70 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enolgor/muxc/examples/basic/client"
+	"github.com/enolgor/muxc/examples/basic/controllers"
+	"github.com/enolgor/muxc/examples/basic/muxc"
+)
+
+// TestClient calls the routes configured by ConfigureMux with the generated client.
+func TestClient(t *testing.T) {
+	mux := http.NewServeMux()
+	muxc.ConfigureMux(mux, controllers.NewController())
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	c := client.NewClient(server.URL+"/", server.Client())
+	ctx := context.Background()
+
+	if err := c.Health(ctx); err != nil {
+		t.Fatal(err)
+	}
+	created, err := c.CreatePet(ctx, controllers.Pet{Name: "Rex", Breed: "Beagle"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (controllers.Pet{ID: 1, Name: "Rex", Breed: "Beagle"}); created != want {
+		t.Errorf("created pet %+v, want %+v", created, want)
+	}
+	if err = c.UpdatePet(ctx, controllers.Pet{ID: created.ID, Name: "Max", Breed: "Beagle"}); err != nil {
+		t.Fatal(err)
+	}
+	pet, err := c.ReadPet(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (controllers.Pet{ID: 1, Name: "Max", Breed: "Beagle"}); pet != want {
+		t.Errorf("read pet %+v, want %+v", pet, want)
+	}
+	pets, err := c.ListPets(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pets) != 1 || *pets[0] != pet {
+		t.Errorf("listed pets %v, want [%+v]", pets, pet)
+	}
+
+	tests := []struct {
+		name   string
+		call   func() error
+		status int
+		body   string
+	}{
+		{"ReadPet", func() error { _, err := c.ReadPet(ctx, 2); return err }, http.StatusBadRequest, "pet not found\n"},
+		{"UpdatePet", func() error { return c.UpdatePet(ctx, controllers.Pet{ID: 2}) }, http.StatusBadRequest, "pet not found\n"},
+		{"Test", func() error { return c.Test(ctx) }, http.StatusInternalServerError, "runtime panic\n"},
+	}
+	for _, test := range tests {
+		var statusErr *client.Error
+		if err := test.call(); !errors.As(err, &statusErr) {
+			t.Errorf("%s: got error %v, want a *client.Error", test.name, err)
+		} else if statusErr.StatusCode != test.status || string(statusErr.Body) != test.body {
+			t.Errorf("%s: got status %d and body %q, want %d and %q", test.name, statusErr.StatusCode, statusErr.Body, test.status, test.body)
+		}
+	}
+}
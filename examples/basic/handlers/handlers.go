@@ -23,6 +23,15 @@ func Test(ctrl controllers.Controller) http.HandlerFunc {
 	}
 }
 
+func Health(w http.ResponseWriter, req *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func FakeLogin(w http.ResponseWriter, req *http.Request) {
+	http.SetCookie(w, &http.Cookie{Name: "session", Value: "dev", Path: "/", HttpOnly: true})
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func ListPets(ctrl controllers.Controller) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		pets, err := ctrl.ListPets()
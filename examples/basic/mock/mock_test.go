@@ -0,0 +1,59 @@
+package mock_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enolgor/muxc/examples/basic/mock"
+	"github.com/enolgor/muxc/muxc/generator"
+	muxcmock "github.com/enolgor/muxc/muxc/mock"
+)
+
+// TestPrefer checks that the generated mock handlers and the mock package select the same example.
+func TestPrefer(t *testing.T) {
+	yamlFile, err := generator.LoadFile("../muxc.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := muxcmock.New(yamlFile, muxcmock.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handlers := map[string]http.Handler{"generated": mock.NewMux(), "mock package": server}
+	tests := []struct {
+		prefer []string
+		status int
+	}{
+		{nil, http.StatusOK},
+		{[]string{"example=notFound"}, http.StatusNotFound},
+		{[]string{`example="notFound"`}, http.StatusNotFound},
+		{[]string{"code=404"}, http.StatusNotFound},
+		{[]string{"code=200"}, http.StatusOK},
+		{[]string{"code=404, example=1"}, http.StatusNotFound},
+		{[]string{"example=1, code=404"}, http.StatusOK},
+		{[]string{"code=404", "example=1"}, http.StatusNotFound},
+		{[]string{"respond-async, wait=5, example=notFound"}, http.StatusNotFound},
+		{[]string{"respond-async"}, http.StatusOK},
+		{[]string{"code=500, example=1"}, http.StatusNotFound},
+		{[]string{"example=missing"}, http.StatusNotFound},
+	}
+	for _, test := range tests {
+		bodies := map[string]string{}
+		for name, handler := range handlers {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/pet/1", nil)
+			for _, value := range test.prefer {
+				req.Header.Add("Prefer", value)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != test.status {
+				t.Errorf("%s: Prefer %q responded %d, want %d", name, test.prefer, rec.Code, test.status)
+			}
+			bodies[name] = rec.Body.String()
+		}
+		if bodies["generated"] != bodies["mock package"] {
+			t.Errorf("Prefer %q: generated body %q, mock package body %q", test.prefer, bodies["generated"], bodies["mock package"])
+		}
+	}
+}
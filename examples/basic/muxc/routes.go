@@ -6,13 +6,23 @@
 package muxc
 
 import (
+	"log/slog"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/enolgor/muxc/examples/basic/assets"
 	"github.com/enolgor/muxc/examples/basic/controllers"
 	"github.com/enolgor/muxc/examples/basic/handlers"
 	"github.com/enolgor/muxc/examples/basic/middlewares"
 	"github.com/enolgor/muxc/middlewares/logger"
-	"log/slog"
+	"github.com/enolgor/muxc/middlewares/proxy"
+	"github.com/enolgor/muxc/middlewares/redirect"
+	"github.com/enolgor/muxc/middlewares/route"
+	"github.com/enolgor/muxc/middlewares/static"
 )
 
 func chain(f http.HandlerFunc, middlewares ...func(http.HandlerFunc) http.HandlerFunc) http.HandlerFunc {
@@ -37,46 +47,293 @@ func Middleware(m func(http.Handler) http.Handler) func(http.HandlerFunc) http.H
 	}
 }
 
+// InvalidWildcard is called when a typed wildcard does not match its declared type,
+// it can be replaced to customize the response (e.g. responding with 404 instead of 400).
+var InvalidWildcard = func(w http.ResponseWriter, req *http.Request, name string, value string) {
+	http.Error(w, "invalid value '"+value+"' for path wildcard '"+name+"'", http.StatusBadRequest)
+}
+
+type wildcard struct {
+	name  string
+	valid func(string) bool
+}
+
+func validate(wildcards ...wildcard) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request) {
+			for _, wc := range wildcards {
+				if value := req.PathValue(wc.name); !wc.valid(value) {
+					InvalidWildcard(w, req, wc.name, value)
+					return
+				}
+			}
+			next(w, req)
+		}
+	}
+}
+
+var (
+	uuidRegexp = regexp.MustCompile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$")
+	slugRegexp = regexp.MustCompile("^[a-z0-9]+(?:-[a-z0-9]+)*$")
+)
+
+func isInt(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
+
+func isInt64(s string) bool {
+	_, err := strconv.ParseInt(s, 10, 64)
+	return err == nil
+}
+
+// PetIDFrom returns the value of the 'id' path wildcard, validated as int64.
+func PetIDFrom(req *http.Request) int64 {
+	value, _ := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	return value
+}
+
+var routeDescriptors = [...]route.Route{
+	{
+		Name:         "ListPets",
+		Method:       "GET",
+		Pattern:      "GET /api/v1/pet",
+		Base:         "/api/v1",
+		Handler:      "handlers.ListPets(ctrl)",
+		Middlewares:  []string{"Middleware(middlewares.RequestID)", "logger", "contentJson"},
+		File:         "v1.yaml",
+		Line:         10,
+		Timeout:      10 * time.Second,
+		MaxBodyBytes: 65536,
+	},
+	{
+		Name:         "ReadPet",
+		Method:       "GET",
+		Pattern:      "GET /api/v1/pet/{id}",
+		Base:         "/api/v1",
+		Handler:      "handlers.ReadPet(ctrl)",
+		Middlewares:  []string{"Middleware(middlewares.RequestID)", "logger", "contentJson"},
+		File:         "v1.yaml",
+		Line:         14,
+		Timeout:      10 * time.Second,
+		MaxBodyBytes: 65536,
+	},
+	{
+		Name:         "CreatePet",
+		Method:       "PUT",
+		Pattern:      "PUT /api/v1/pet",
+		Base:         "/api/v1",
+		Handler:      "handlers.CreatePet(ctrl)",
+		Middlewares:  []string{"Middleware(middlewares.RequestID)", "logger", "aJson"},
+		File:         "v1.yaml",
+		Line:         25,
+		Timeout:      10 * time.Second,
+		MaxBodyBytes: 65536,
+	},
+	{
+		Name:          "UpdatePet",
+		Method:        "POST",
+		Pattern:       "POST /api/v1/pet",
+		Base:          "/api/v1",
+		Handler:       "handlers.UpdatePet(ctrl)",
+		Middlewares:   []string{"Middleware(middlewares.RequestID)", "logger", "contentJson"},
+		File:          "v1.yaml",
+		Line:          31,
+		MaxBodyBytes:  10485760,
+		ReadDeadline:  5 * time.Minute,
+		WriteDeadline: 5 * time.Minute,
+	},
+	{
+		Name:         "DeletePet",
+		Method:       "DELETE",
+		Pattern:      "DELETE /api/v1/pet",
+		Base:         "/api/v1",
+		Handler:      "handlers.DeletePet(ctrl)",
+		Middlewares:  []string{"Middleware(middlewares.RequestID)", "logger", "Middleware(middlewares.SetHeader(\"Cache-Control\", \"no-store\"))"},
+		File:         "v1.yaml",
+		Line:         37,
+		Timeout:      10 * time.Second,
+		MaxBodyBytes: 65536,
+	},
+	{
+		Name:         "Health",
+		Method:       "GET",
+		Pattern:      "GET /api/v1/health",
+		Base:         "/api/v1",
+		Handler:      "handlers.Health",
+		Middlewares:  []string{"Middleware(middlewares.RequestID)"},
+		File:         "v1.yaml",
+		Line:         38,
+		Timeout:      10 * time.Second,
+		MaxBodyBytes: 65536,
+	},
+	{
+		Name:        "Test",
+		Method:      "GET",
+		Pattern:     "GET /api/v2/pet",
+		Base:        "/api/v2",
+		Handler:     "handlers.Test(ctrl)",
+		Middlewares: []string{"logger", "middlewares.Recover", "contentJson", "middlewares.InterceptErrorStatus", "middlewares.InterceptContentSniffer"},
+		File:        "v1.yaml",
+		Line:        44,
+	},
+	{
+		Name:     "FakeLogin",
+		Method:   "POST",
+		Pattern:  "POST /dev/login",
+		Base:     "/dev",
+		Profiles: []string{"dev"},
+		Handler:  "handlers.FakeLogin",
+		File:     "v1.yaml",
+		Line:     49,
+	},
+	{
+		Name:        "",
+		Method:      "",
+		Pattern:     "/legacy/pets/{path...}",
+		Base:        "/legacy",
+		Handler:     "proxy.New(legacyURL, proxy.Options{Path: \"/v0/pets/{path}\", Headers: map[string]string{\"X-Forwarded-By\": \"muxc\"}, Timeout: 5 * time.Second})",
+		Middlewares: []string{"logger"},
+		File:        "v1.yaml",
+		Line:        54,
+	},
+	{
+		Name:        "",
+		Method:      "GET",
+		Pattern:     "GET /legacy/pet/{id}",
+		Base:        "/legacy",
+		Handler:     "redirect.New(\"/api/v1/pet/{id}\", 308)",
+		Middlewares: []string{"logger"},
+		File:        "v1.yaml",
+		Line:        61,
+	},
+	{
+		Name:        "",
+		Method:      "GET",
+		Pattern:     "GET /app/{path...}",
+		Base:        "/app",
+		Handler:     "http.StripPrefix(\"/app\", static.New(assets.FS, static.Options{Root: \"public\", SPA: true, CacheControl: \"public, max-age=3600\", Precompressed: true})).ServeHTTP",
+		Middlewares: []string{"logger"},
+		File:        "v1.yaml",
+		Line:        69,
+	},
+}
+
+// routeEnabled reports for each route descriptor whether the profiles of the route are enabled.
+var routeEnabled = [...]bool{
+	true,
+	true,
+	true,
+	true,
+	true,
+	true,
+	true,
+	profileDev,
+	true,
+	true,
+	true,
+}
+
+// Routes returns the descriptors of all the routes registered by ConfigureMux.
+func Routes() []route.Route {
+	routes := make([]route.Route, 0, len(routeDescriptors))
+	for i := range routeDescriptors {
+		if routeEnabled[i] {
+			routes = append(routes, routeDescriptors[i])
+		}
+	}
+	return routes
+}
+
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return strings.Join(segments, "/")
+}
+
+// URLReadPet returns the path of the ReadPet route (/api/v1/pet/{id}).
+func URLReadPet(id int64) string {
+	return "/api/v1/pet/" + strconv.FormatInt(id, 10)
+}
+
 func ConfigureMux(mux *http.ServeMux, ctrl controllers.Controller) {
-	acceptJson := Middleware(middlewares.SetHeader("Accept", "application/json"))
 	contentJson := Middleware(middlewares.SetHeader("Content-Type", "application/json"))
-	json := stack(contentJson, acceptJson)
+	acceptJson := Middleware(middlewares.SetHeader("Accept", "application/json"))
+	aJson := stack(contentJson, acceptJson)
 	logger := logger.New(slog.Default(), logger.InternalServerError(slog.LevelError), logger.BadRequest(slog.LevelWarn))
+	legacyURL := "http://localhost:8081"
 	mux.Handle("GET /api/v1/pet", chain(
 		handlers.ListPets(ctrl),
+		route.Limit(&routeDescriptors[0]),
 		contentJson,
 		logger,
 		Middleware(middlewares.RequestID),
+		route.Inject(&routeDescriptors[0]),
 	))
 	mux.Handle("GET /api/v1/pet/{id}", chain(
 		handlers.ReadPet(ctrl),
+		stack(validate(wildcard{"id", isInt64}), route.Limit(&routeDescriptors[1])),
 		contentJson,
 		logger,
 		Middleware(middlewares.RequestID),
+		route.Inject(&routeDescriptors[1]),
 	))
 	mux.Handle("PUT /api/v1/pet", chain(
 		handlers.CreatePet(ctrl),
-		json,
+		route.Limit(&routeDescriptors[2]),
+		aJson,
 		logger,
 		Middleware(middlewares.RequestID),
+		route.Inject(&routeDescriptors[2]),
 	))
 	mux.Handle("POST /api/v1/pet", chain(
 		handlers.UpdatePet(ctrl),
+		route.Limit(&routeDescriptors[3]),
 		contentJson,
 		logger,
 		Middleware(middlewares.RequestID),
+		route.Inject(&routeDescriptors[3]),
 	))
 	mux.Handle("DELETE /api/v1/pet", chain(
 		handlers.DeletePet(ctrl),
+		route.Limit(&routeDescriptors[4]),
+		Middleware(middlewares.SetHeader("Cache-Control", "no-store")),
 		logger,
 		Middleware(middlewares.RequestID),
+		route.Inject(&routeDescriptors[4]),
+	))
+	mux.Handle("GET /api/v1/health", chain(
+		handlers.Health,
+		route.Limit(&routeDescriptors[5]),
+		Middleware(middlewares.RequestID),
+		route.Inject(&routeDescriptors[5]),
 	))
 	mux.Handle("GET /api/v2/pet", chain(
 		handlers.Test(ctrl),
-		middlewares.Recover,
 		middlewares.InterceptContentSniffer,
 		middlewares.InterceptErrorStatus,
 		contentJson,
+		middlewares.Recover,
+		logger,
+		route.Inject(&routeDescriptors[6]),
+	))
+	mux.Handle("/legacy/pets/{path...}", chain(
+		proxy.New(legacyURL, proxy.Options{Path: "/v0/pets/{path}", Headers: map[string]string{"X-Forwarded-By": "muxc"}, Timeout: 5 * time.Second}),
+		logger,
+		route.Inject(&routeDescriptors[8]),
+	))
+	mux.Handle("GET /legacy/pet/{id}", chain(
+		redirect.New("/api/v1/pet/{id}", 308),
+		validate(wildcard{"id", isInt64}),
+		logger,
+		route.Inject(&routeDescriptors[9]),
+	))
+	mux.Handle("GET /app/{path...}", chain(
+		http.StripPrefix("/app", static.New(assets.FS, static.Options{Root: "public", SPA: true, CacheControl: "public, max-age=3600", Precompressed: true})).ServeHTTP,
 		logger,
+		route.Inject(&routeDescriptors[10]),
 	))
+	configureDevRoutes(mux)
 }
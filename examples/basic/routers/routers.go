@@ -0,0 +1,18 @@
+// Package routers registers the same routes in an http.ServeMux (servemux) and in a chi router
+// (chirouter), to check that their handlers get the same path values, and routes returning the
+// registration errors instead of panicking (returnerrors).
+package routers
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Echo responds with the path values of the given wildcards, one name=value per line.
+func Echo(names ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		for _, name := range names {
+			fmt.Fprintf(w, "%s=%s\n", name, req.PathValue(name))
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package routers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/enolgor/muxc/examples/basic/routers/chirouter"
+	"github.com/enolgor/muxc/examples/basic/routers/returnerrors"
+	"github.com/enolgor/muxc/examples/basic/routers/servemux"
+	"github.com/go-chi/chi/v5"
+)
+
+// TestPathValues checks that the handlers registered in both routers get the same path values.
+func TestPathValues(t *testing.T) {
+	mux := http.NewServeMux()
+	servemux.ConfigureMux(mux)
+	router := chi.NewRouter()
+	chirouter.ConfigureMux(router)
+	handlers := map[string]http.Handler{"servemux": mux, "chi": router}
+	tests := []struct {
+		url    string
+		status int
+		body   string
+	}{
+		{"/pets/rex", http.StatusOK, "name=rex\n"},
+		{"/pets/a%20b", http.StatusOK, "name=a b\n"},
+		{"/pets/caf%C3%A9", http.StatusOK, "name=café\n"},
+		{"/pets/a%2Fb", http.StatusOK, "name=a/b\n"},
+		{"/pets/a%25b", http.StatusOK, "name=a%b\n"},
+		{"/pets/a%2Fb/toys/c%25d", http.StatusOK, "name=a/b\ntoy=c%d\n"},
+		{"/pets/1/age", http.StatusOK, "id=1\n"},
+		{"/pets/%31/age", http.StatusOK, "id=1\n"},
+		{"/pets/x/age", http.StatusBadRequest, "invalid value 'x' for path wildcard 'id'\n"},
+		{"/files/a/b", http.StatusOK, "path=a/b\n"},
+		{"/files/a%25b", http.StatusOK, "path=a%b\n"},
+		{"/files/a/b%2Fc", http.StatusOK, "path=a/b/c\n"},
+		{"/files/a%20b/c%2Fd%25e", http.StatusOK, "path=a b/c/d%e\n"},
+	}
+	for _, test := range tests {
+		for name, handler := range handlers {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.url, nil))
+			if rec.Code != test.status || rec.Body.String() != test.body {
+				t.Errorf("%s: %s responded %d %q, want %d %q", name, test.url, rec.Code, rec.Body.String(), test.status, test.body)
+			}
+		}
+	}
+}
+
+// TestReturnErrors checks that ConfigureMux returns the registration errors with the route location.
+func TestReturnErrors(t *testing.T) {
+	custom := func(w http.ResponseWriter, req *http.Request) {}
+	if err := returnerrors.ConfigureMux(http.NewServeMux(), nil); err == nil || err.Error() != "muxc.yaml:11: route 'GET /custom' handler custom is nil" {
+		t.Errorf("got error %v with a nil handler", err)
+	}
+	mux := http.NewServeMux()
+	if err := returnerrors.ConfigureMux(mux, custom); err != nil {
+		t.Fatal(err)
+	}
+	// the mux rejects the patterns registered twice
+	err := returnerrors.ConfigureMux(mux, custom)
+	if want := "muxc.yaml:10: route 'GET /pets/{name}' registration failed: "; err == nil || !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("got error %v registering the routes twice, want %q", err, want)
+	}
+}
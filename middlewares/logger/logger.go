@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"time"
 
+	"github.com/enolgor/muxc/middlewares/route"
 	"github.com/google/uuid"
 )
 
@@ -106,7 +107,11 @@ func rangeStatusMatcher(minIncl, maxIncl int, level slog.Level) LoggerOption {
 }
 
 var defaultLogFunc LogFunc = func(log *slog.Logger, ctx context.Context, level slog.Level, requestId string, status int, size int, duration time.Duration, req *http.Request) {
-	log.Log(ctx, level, "request", "requestId", requestId, "method", req.Method, "path", req.URL.Path, "query", req.URL.RawQuery, "addr", req.RemoteAddr, "status", status, "size", size, "duration", duration)
+	args := []any{"requestId", requestId, "method", req.Method, "path", req.URL.Path, "query", req.URL.RawQuery, "addr", req.RemoteAddr, "status", status, "size", size, "duration", duration}
+	if r := route.GetRoute(req); r != nil {
+		args = append(args, "route", r.Name, "pattern", r.Pattern)
+	}
+	log.Log(ctx, level, "request", args...)
 }
 
 func New(log *slog.Logger, opts ...LoggerOption) func(http.HandlerFunc) http.HandlerFunc {
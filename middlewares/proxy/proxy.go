@@ -0,0 +1,72 @@
+// Package proxy serves the reverse proxy routes declared in muxc yaml configurations.
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"time"
+
+	"github.com/enolgor/muxc/middlewares/route"
+)
+
+type Options struct {
+	// Path is the template of the upstream request path, with its wildcards replaced by the path values
+	// of the request (see route.Expand), appended to the target path. The request path is used if empty.
+	Path string
+	// Headers are set in the upstream requests.
+	Headers map[string]string
+	// Timeout limits the duration of the upstream requests, responding with 504 Gateway Timeout when exceeded.
+	Timeout time.Duration
+}
+
+// New returns a handler forwarding requests to the target url with a httputil.ReverseProxy,
+// it panics if the target is not an absolute url.
+func New(target string, opts Options) http.HandlerFunc {
+	handler, err := NewE(target, opts)
+	if err != nil {
+		panic(err.Error())
+	}
+	return handler
+}
+
+// NewE is like New but returns an error if the target is not an absolute url.
+func NewE(target string, opts Options) (http.HandlerFunc, error) {
+	upstream, err := url.Parse(target)
+	if err != nil || !upstream.IsAbs() || upstream.Host == "" {
+		return nil, fmt.Errorf("invalid proxy target '%s', it should be an absolute url", target)
+	}
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			if opts.Path != "" {
+				r.Out.URL.Path, r.Out.URL.RawPath = "", ""
+				if path, err := url.Parse(route.Expand(opts.Path, r.In)); err == nil {
+					r.Out.URL.Path, r.Out.URL.RawPath = path.Path, path.RawPath
+				}
+			}
+			r.SetURL(upstream)
+			r.SetXForwarded()
+			for key, value := range opts.Headers {
+				r.Out.Header.Set(key, value)
+			}
+		},
+		ErrorHandler: func(w http.ResponseWriter, req *http.Request, err error) {
+			if errors.Is(err, context.DeadlineExceeded) {
+				http.Error(w, http.StatusText(http.StatusGatewayTimeout), http.StatusGatewayTimeout)
+				return
+			}
+			http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+		},
+	}
+	return func(w http.ResponseWriter, req *http.Request) {
+		if opts.Timeout > 0 {
+			ctx, cancel := context.WithTimeout(req.Context(), opts.Timeout)
+			defer cancel()
+			req = req.WithContext(ctx)
+		}
+		proxy.ServeHTTP(w, req)
+	}, nil
+}
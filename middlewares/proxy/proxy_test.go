@@ -0,0 +1,121 @@
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/slow" {
+			select {
+			case <-req.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		w.Header().Set("X-Path", req.URL.EscapedPath())
+		w.Header().Set("X-Query", req.URL.RawQuery)
+		w.Header().Set("X-Api-Key", req.Header.Get("X-Api-Key"))
+		w.Header().Set("X-Forwarded", req.Header.Get("X-Forwarded-Host"))
+	}))
+	defer upstream.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	tests := []struct {
+		name    string
+		pattern string
+		target  string
+		opts    Options
+		path    string
+		status  int
+		headers map[string]string
+	}{
+		{
+			name:    "request path",
+			pattern: "GET /pets/{id}",
+			target:  upstream.URL + "/api",
+			path:    "/pets/1?full=true",
+			status:  http.StatusOK,
+			headers: map[string]string{"X-Path": "/api/pets/1", "X-Query": "full=true", "X-Forwarded": "example.com"},
+		},
+		{
+			name:    "path template",
+			pattern: "GET /legacy/pets/{id}",
+			target:  upstream.URL + "/api",
+			opts:    Options{Path: "/v2/pets/{id}/details"},
+			path:    "/legacy/pets/1",
+			status:  http.StatusOK,
+			headers: map[string]string{"X-Path": "/api/v2/pets/1/details"},
+		},
+		{
+			name:    "escaped path value",
+			pattern: "GET /legacy/pets/{name}",
+			target:  upstream.URL,
+			opts:    Options{Path: "/pets/{name}"},
+			path:    "/legacy/pets/a%20b",
+			status:  http.StatusOK,
+			headers: map[string]string{"X-Path": "/pets/a%20b"},
+		},
+		{
+			name:    "remainder",
+			pattern: "GET /legacy/{path...}",
+			target:  upstream.URL,
+			opts:    Options{Path: "/api/{path...}"},
+			path:    "/legacy/pets/1",
+			status:  http.StatusOK,
+			headers: map[string]string{"X-Path": "/api/pets/1"},
+		},
+		{
+			name:    "headers",
+			pattern: "GET /pets",
+			target:  upstream.URL,
+			opts:    Options{Headers: map[string]string{"X-Api-Key": "secret"}},
+			path:    "/pets",
+			status:  http.StatusOK,
+			headers: map[string]string{"X-Api-Key": "secret"},
+		},
+		{
+			name:    "timeout",
+			pattern: "GET /slow",
+			target:  upstream.URL + "/api",
+			opts:    Options{Timeout: 10 * time.Millisecond},
+			path:    "/slow",
+			status:  http.StatusGatewayTimeout,
+		},
+		{
+			name:    "unreachable upstream",
+			pattern: "GET /pets",
+			target:  closed.URL,
+			path:    "/pets",
+			status:  http.StatusBadGateway,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.Handle(test.pattern, New(test.target, test.opts))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.path, nil))
+			if rec.Code != test.status {
+				t.Errorf("got status %d, want %d", rec.Code, test.status)
+			}
+			for name, value := range test.headers {
+				if got := rec.Header().Get(name); got != value {
+					t.Errorf("got %s header %q, want %q", name, got, value)
+				}
+			}
+		})
+	}
+}
+
+func TestNewInvalidTarget(t *testing.T) {
+	for _, target := range []string{"/api", "localhost:8080", "http://"} {
+		if _, err := NewE(target, Options{}); err == nil || !strings.Contains(err.Error(), "invalid proxy target '"+target+"'") {
+			t.Errorf("%s: got error %v", target, err)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+// Package redirect serves the redirect routes declared in muxc yaml configurations.
+package redirect
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/enolgor/muxc/middlewares/route"
+)
+
+// New returns a handler redirecting requests to a target template, with its wildcards replaced
+// by the path values of the request (see route.Expand). The query of the request is kept
+// unless the target has its own.
+func New(target string, status int) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		location := route.Expand(target, req)
+		if req.URL.RawQuery != "" && !strings.Contains(location, "?") {
+			location += "?" + req.URL.RawQuery
+		}
+		http.Redirect(w, req, location, status)
+	}
+}
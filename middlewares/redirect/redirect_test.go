@@ -0,0 +1,31 @@
+package redirect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		target   string
+		status   int
+		path     string
+		location string
+	}{
+		{target: "/animals/{id}", status: http.StatusMovedPermanently, path: "/pets/1", location: "/animals/1"},
+		{target: "/animals/{id}", status: http.StatusFound, path: "/pets/1?full=true", location: "/animals/1?full=true"},
+		// the query of the target replaces the one of the request
+		{target: "/animals/{id}?v=2", status: http.StatusFound, path: "/pets/1?full=true", location: "/animals/1?v=2"},
+		{target: "https://example.com/pets/{id}", status: http.StatusPermanentRedirect, path: "/pets/a%20b", location: "https://example.com/pets/a%20b"},
+	}
+	for _, test := range tests {
+		mux := http.NewServeMux()
+		mux.Handle("GET /pets/{id}", New(test.target, test.status))
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.path, nil))
+		if rec.Code != test.status || rec.Header().Get("Location") != test.location {
+			t.Errorf("%s to %s: got %d %q, want %d %q", test.path, test.target, rec.Code, rec.Header().Get("Location"), test.status, test.location)
+		}
+	}
+}
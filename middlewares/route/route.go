@@ -0,0 +1,164 @@
+package route
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Route describes a route registered by muxc generated code, as defined in the yaml configuration.
+// Middlewares holds the middleware expressions of the route, from the outermost to the innermost,
+// and Profiles the build tags the route is registered with (any of them), if any. The limits of the
+// route (if not zero) are applied by Inject (the deadlines) and Limit (the timeout and body size).
+type Route struct {
+	Name        string
+	Method      string
+	Pattern     string
+	Base        string
+	Tags        []string
+	Profiles    []string
+	Handler     string
+	Middlewares []string
+	File        string
+	Line        int
+
+	Timeout       time.Duration
+	MaxBodyBytes  int64
+	ReadDeadline  time.Duration
+	WriteDeadline time.Duration
+}
+
+type routeContextKey int
+
+const route_descriptor routeContextKey = iota
+
+func NewContext(ctx context.Context, route *Route) context.Context {
+	return context.WithValue(ctx, route_descriptor, route)
+}
+
+func FromContext(ctx context.Context) (*Route, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	route, ok := ctx.Value(route_descriptor).(*Route)
+	return route, ok
+}
+
+func GetRoute(req *http.Request) *Route {
+	if req == nil {
+		return nil
+	}
+	route, _ := FromContext(req.Context())
+	return route
+}
+
+// Inject returns a middleware that stores the route descriptor in the request context and sets the
+// read and write deadlines of the route, overriding the ReadTimeout and WriteTimeout of the server.
+// Deadlines are ignored if the response writer doesn't support them (see http.ResponseController).
+func Inject(route *Route) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request) {
+			if route.ReadDeadline > 0 || route.WriteDeadline > 0 {
+				rc := http.NewResponseController(w)
+				if route.ReadDeadline > 0 {
+					rc.SetReadDeadline(time.Now().Add(route.ReadDeadline))
+				}
+				if route.WriteDeadline > 0 {
+					rc.SetWriteDeadline(time.Now().Add(route.WriteDeadline))
+				}
+			}
+			next.ServeHTTP(w, req.WithContext(NewContext(req.Context(), route)))
+		}
+	}
+}
+
+// Limit returns a middleware that applies the timeout and body size limit of the route. The timeout
+// cancels the request context, requests whose handler returns after it without writing a response get
+// a 503 Service Unavailable response. Unlike http.TimeoutHandler the response is not buffered, so
+// handlers can stream it, but they should return once the context is done (the write deadline of
+// the route limits the writes of the ones that don't). Requests declaring a body larger than the limit
+// get a 413 Request Entity Too Large response, and bodies without Content-Length fail to be read past
+// the limit with a *http.MaxBytesError.
+func Limit(route *Route) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request) {
+			if route.MaxBodyBytes > 0 {
+				if req.ContentLength > route.MaxBodyBytes {
+					http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+					return
+				}
+				req.Body = http.MaxBytesReader(w, req.Body, route.MaxBodyBytes)
+			}
+			if route.Timeout <= 0 {
+				next(w, req)
+				return
+			}
+			ctx, cancel := context.WithTimeout(req.Context(), route.Timeout)
+			defer cancel()
+			tw := &timeoutWriter{ResponseWriter: w}
+			next(tw, req.WithContext(ctx))
+			if !tw.written && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			}
+		}
+	}
+}
+
+// timeoutWriter records whether the handler of a route with a timeout wrote the response. It is
+// a http.Flusher and a http.Hijacker, which fail as with http.ResponseController if the writer it
+// wraps is not.
+type timeoutWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	// informational responses are followed by the actual one
+	tw.written = tw.written || status >= 200
+	tw.ResponseWriter.WriteHeader(status)
+}
+
+func (tw *timeoutWriter) Write(data []byte) (int, error) {
+	tw.written = true
+	return tw.ResponseWriter.Write(data)
+}
+
+func (tw *timeoutWriter) Flush() {
+	tw.written = true
+	http.NewResponseController(tw.ResponseWriter).Flush()
+}
+
+func (tw *timeoutWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	tw.written = true
+	return http.NewResponseController(tw.ResponseWriter).Hijack()
+}
+
+func (tw *timeoutWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
+
+// Expand returns a path template with its {name} (or {name...}) wildcards replaced by the
+// escaped path values of the request, e.g. /pets/{id} is expanded to /pets/1.
+func Expand(template string, req *http.Request) string {
+	expanded := &strings.Builder{}
+	for {
+		start := strings.Index(template, "{")
+		end := strings.Index(template[max(start, 0):], "}") + start
+		if start == -1 || end < start {
+			expanded.WriteString(template)
+			return expanded.String()
+		}
+		expanded.WriteString(template[:start])
+		segments := strings.Split(req.PathValue(strings.TrimSuffix(template[start+1:end], "...")), "/")
+		for i := range segments {
+			segments[i] = url.PathEscape(segments[i])
+		}
+		expanded.WriteString(strings.Join(segments, "/"))
+		template = template[end+1:]
+	}
+}
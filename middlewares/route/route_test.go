@@ -0,0 +1,139 @@
+package route
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLimit(t *testing.T) {
+	waitContext := func(w http.ResponseWriter, req *http.Request) {
+		<-req.Context().Done()
+	}
+	tests := []struct {
+		name    string
+		route   Route
+		body    string
+		handler http.HandlerFunc
+		status  int
+	}{
+		{
+			name:    "no limits",
+			handler: func(w http.ResponseWriter, req *http.Request) {},
+			status:  http.StatusOK,
+		},
+		{
+			name:    "timeout without response",
+			route:   Route{Timeout: time.Millisecond},
+			handler: waitContext,
+			status:  http.StatusServiceUnavailable,
+		},
+		{
+			name:  "timeout after response",
+			route: Route{Timeout: time.Millisecond},
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+				waitContext(w, req)
+			},
+			status: http.StatusAccepted,
+		},
+		{
+			name:  "timeout with handler response",
+			route: Route{Timeout: time.Millisecond},
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				waitContext(w, req)
+				http.Error(w, "gateway timeout", http.StatusGatewayTimeout)
+			},
+			status: http.StatusGatewayTimeout,
+		},
+		{
+			name:  "flush before timeout",
+			route: Route{Timeout: time.Millisecond},
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				w.(http.Flusher).Flush()
+				waitContext(w, req)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:    "body over limit",
+			route:   Route{MaxBodyBytes: 4},
+			body:    "12345",
+			handler: func(w http.ResponseWriter, req *http.Request) {},
+			status:  http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:  "body within limit",
+			route: Route{MaxBodyBytes: 5},
+			body:  "12345",
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				if _, err := io.ReadAll(req.Body); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+				}
+			},
+			status: http.StatusOK,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(test.body))
+			Limit(&test.route)(test.handler)(rec, req)
+			if rec.Code != test.status {
+				t.Errorf("got status %d, want %d", rec.Code, test.status)
+			}
+		})
+	}
+}
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		path     string
+		template string
+		want     string
+	}{
+		{pattern: "/pets/{id}", path: "/pets/1", template: "/animals/{id}", want: "/animals/1"},
+		{pattern: "/pets/{id}", path: "/pets/1", template: "/animals/{id}/{missing}", want: "/animals/1/"},
+		{pattern: "/pets/{name}", path: "/pets/a%20b", template: "/animals/{name}", want: "/animals/a%20b"},
+		{pattern: "/pets/{rest...}", path: "/pets/a/b%20c", template: "/animals/{rest...}", want: "/animals/a/b%20c"},
+		{pattern: "/pets/{rest...}", path: "/pets/a/b", template: "/animals/{rest}", want: "/animals/a/b"},
+		{pattern: "/pets/{id}", path: "/pets/1", template: "/animals/{id", want: "/animals/{id"},
+	}
+	for _, test := range tests {
+		mux := http.NewServeMux()
+		got := ""
+		mux.HandleFunc(test.pattern, func(w http.ResponseWriter, req *http.Request) {
+			got = Expand(test.template, req)
+		})
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, test.path, nil))
+		if got != test.want {
+			t.Errorf("%s expanded for %s: got %q, want %q", test.template, test.path, got, test.want)
+		}
+	}
+}
+
+func TestInject(t *testing.T) {
+	descriptor := &Route{Name: "ReadPet", Method: http.MethodGet, Pattern: "/pets/{id}", ReadDeadline: time.Second, WriteDeadline: time.Second}
+	var got *Route
+	handler := Inject(descriptor)(func(w http.ResponseWriter, req *http.Request) {
+		got = GetRoute(req)
+	})
+	// the deadlines are ignored as the recorder doesn't support them
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/pets/1", nil))
+	if got != descriptor {
+		t.Errorf("got route %v, want %v", got, descriptor)
+	}
+	if route := GetRoute(httptest.NewRequest(http.MethodGet, "/pets/1", nil)); route != nil {
+		t.Errorf("got route %v without Inject, want nil", route)
+	}
+	if route := GetRoute(nil); route != nil {
+		t.Errorf("got route %v for a nil request, want nil", route)
+	}
+	if _, ok := FromContext(nil); ok {
+		t.Error("got a route from a nil context")
+	}
+}
@@ -0,0 +1,130 @@
+// Package static serves the files of static routes declared in muxc yaml configurations.
+package static
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/enolgor/muxc/middlewares/header"
+)
+
+type Options struct {
+	// Root is the directory of the file system to serve, the whole file system is served if empty.
+	Root string
+	// SPA serves the root index.html for paths without a file, so that client side routing works.
+	SPA bool
+	// CacheControl is set as the Cache-Control header of the responses, if not empty.
+	CacheControl string
+	// Precompressed serves the .br or .gz variant of a file if it exists and the client accepts its encoding.
+	Precompressed bool
+}
+
+// encodings are the precompressed variants served, by order of preference.
+var encodings = []struct {
+	name      string
+	extension string
+}{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// New returns a handler serving the files of fsys, the request path is the name of the file
+// (so the route prefix should be stripped with http.StripPrefix). It panics if the root is not a
+// directory of fsys.
+func New(fsys fs.FS, opts Options) http.HandlerFunc {
+	handler, err := NewE(fsys, opts)
+	if err != nil {
+		panic(err.Error())
+	}
+	return handler
+}
+
+// NewE is like New but returns an error if the root is not a directory of fsys.
+func NewE(fsys fs.FS, opts Options) (http.HandlerFunc, error) {
+	if opts.Root != "" {
+		if info, err := fs.Stat(fsys, opts.Root); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("invalid static root '%s', it should be a directory of the file system", opts.Root)
+		}
+		sub, err := fs.Sub(fsys, opts.Root)
+		if err != nil {
+			return nil, fmt.Errorf("invalid static root '%s': %w", opts.Root, err)
+		}
+		fsys = sub
+	}
+	server := http.FileServerFS(fsys)
+	return func(w http.ResponseWriter, req *http.Request) {
+		name := strings.TrimPrefix(path.Clean("/"+req.URL.Path), "/")
+		if name == "" {
+			name = "."
+		}
+		info, err := fs.Stat(fsys, name)
+		if errors.Is(err, fs.ErrNotExist) && opts.SPA {
+			r := req.Clone(req.Context())
+			r.URL.Path, r.URL.RawPath = "/", ""
+			req, name = r, "."
+			info, err = fs.Stat(fsys, name)
+		}
+		if opts.CacheControl != "" {
+			w.Header().Set(header.CacheControl, opts.CacheControl)
+		}
+		if opts.Precompressed && err == nil {
+			w.Header().Add(header.Vary, header.AcceptEncoding)
+			if info.IsDir() && strings.HasSuffix(req.URL.Path, "/") {
+				name = path.Join(name, "index.html")
+			}
+			if serveCompressed(w, req, fsys, name) {
+				return
+			}
+		}
+		server.ServeHTTP(w, req)
+	}, nil
+}
+
+// accepts reports whether an Accept-Encoding header value accepts an encoding.
+func accepts(values []string, encoding string) bool {
+	for _, value := range values {
+		for _, accepted := range strings.Split(value, ",") {
+			accepted, params, _ := strings.Cut(accepted, ";")
+			if strings.TrimSpace(accepted) == encoding && strings.ReplaceAll(params, " ", "") != "q=0" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func serveCompressed(w http.ResponseWriter, req *http.Request, fsys fs.FS, name string) bool {
+	if info, err := fs.Stat(fsys, name); err != nil || info.IsDir() {
+		return false
+	}
+	for _, encoding := range encodings {
+		if !accepts(req.Header.Values(header.AcceptEncoding), encoding.name) {
+			continue
+		}
+		file, err := fsys.Open(name + encoding.extension)
+		if err != nil {
+			continue
+		}
+		defer file.Close()
+		info, err := file.Stat()
+		content, seekable := file.(io.ReadSeeker)
+		if err != nil || info.IsDir() || !seekable {
+			continue
+		}
+		contentType := mime.TypeByExtension(path.Ext(name))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		w.Header().Set(header.ContentType, contentType)
+		w.Header().Set(header.ContentEncoding, encoding.name)
+		http.ServeContent(w, req, name, info.ModTime(), content)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package static
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestNew(t *testing.T) {
+	fsys := fstest.MapFS{
+		"public/index.html":       {Data: []byte("index")},
+		"public/index.html.gz":    {Data: []byte("gzip index")},
+		"public/app.js":           {Data: []byte("app")},
+		"public/app.js.br":        {Data: []byte("br app")},
+		"public/app.js.gz":        {Data: []byte("gzip app")},
+		"public/docs/index.html":  {Data: []byte("docs")},
+		"public/docs/readme.html": {Data: []byte("readme")},
+		"public/style.css":        {Data: []byte("style")},
+		"private/secret.txt":      {Data: []byte("secret")},
+	}
+	tests := []struct {
+		name           string
+		opts           Options
+		path           string
+		acceptEncoding string
+		status         int
+		body           string
+		headers        map[string]string
+	}{
+		{name: "file", opts: Options{Root: "public"}, path: "/app.js", status: http.StatusOK, body: "app"},
+		{name: "index", opts: Options{Root: "public"}, path: "/", status: http.StatusOK, body: "index"},
+		{name: "outside of the root", opts: Options{Root: "public"}, path: "/../private/secret.txt", status: http.StatusNotFound},
+		{name: "whole file system", path: "/private/secret.txt", status: http.StatusOK, body: "secret"},
+		{name: "missing file", opts: Options{Root: "public"}, path: "/pets/1", status: http.StatusNotFound},
+		// the SPA fallback serves the root index.html for paths without a file
+		{name: "spa fallback", opts: Options{Root: "public", SPA: true}, path: "/pets/1", status: http.StatusOK, body: "index"},
+		{name: "spa file", opts: Options{Root: "public", SPA: true}, path: "/style.css", status: http.StatusOK, body: "style"},
+		{
+			name:    "cache control",
+			opts:    Options{Root: "public", CacheControl: "max-age=60"},
+			path:    "/style.css",
+			status:  http.StatusOK,
+			body:    "style",
+			headers: map[string]string{"Cache-Control": "max-age=60"},
+		},
+		// precompressed variants are served by order of preference
+		{
+			name:           "brotli",
+			opts:           Options{Root: "public", Precompressed: true},
+			path:           "/app.js",
+			acceptEncoding: "gzip, br",
+			status:         http.StatusOK,
+			body:           "br app",
+			headers:        map[string]string{"Content-Encoding": "br", "Content-Type": "text/javascript; charset=utf-8", "Vary": "Accept-Encoding"},
+		},
+		{
+			name:           "gzip",
+			opts:           Options{Root: "public", Precompressed: true},
+			path:           "/app.js",
+			acceptEncoding: "gzip, br;q=0",
+			status:         http.StatusOK,
+			body:           "gzip app",
+			headers:        map[string]string{"Content-Encoding": "gzip", "Vary": "Accept-Encoding"},
+		},
+		{
+			name:    "not accepted",
+			opts:    Options{Root: "public", Precompressed: true},
+			path:    "/app.js",
+			status:  http.StatusOK,
+			body:    "app",
+			headers: map[string]string{"Content-Encoding": "", "Vary": "Accept-Encoding"},
+		},
+		{
+			name:           "without variant",
+			opts:           Options{Root: "public", Precompressed: true},
+			path:           "/style.css",
+			acceptEncoding: "br, gzip",
+			status:         http.StatusOK,
+			body:           "style",
+			headers:        map[string]string{"Content-Encoding": "", "Vary": "Accept-Encoding"},
+		},
+		{
+			name:           "directory index",
+			opts:           Options{Root: "public", Precompressed: true},
+			path:           "/",
+			acceptEncoding: "gzip",
+			status:         http.StatusOK,
+			body:           "gzip index",
+			headers:        map[string]string{"Content-Encoding": "gzip", "Content-Type": "text/html; charset=utf-8"},
+		},
+		{
+			name:           "spa fallback variant",
+			opts:           Options{Root: "public", SPA: true, Precompressed: true},
+			path:           "/pets/1",
+			acceptEncoding: "gzip",
+			status:         http.StatusOK,
+			body:           "gzip index",
+			headers:        map[string]string{"Content-Encoding": "gzip", "Vary": "Accept-Encoding"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler, err := NewE(fsys, test.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.URL.Path = test.path
+			if test.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", test.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+			if rec.Code != test.status {
+				t.Errorf("got status %d, want %d", rec.Code, test.status)
+			}
+			if test.body != "" && rec.Body.String() != test.body {
+				t.Errorf("got body %q, want %q", rec.Body.String(), test.body)
+			}
+			for name, value := range test.headers {
+				if got := rec.Header().Get(name); got != value {
+					t.Errorf("got %s header %q, want %q", name, got, value)
+				}
+			}
+		})
+	}
+}
+
+func TestNewInvalidRoot(t *testing.T) {
+	fsys := fstest.MapFS{"public/index.html": {Data: []byte("index")}}
+	for _, root := range []string{"missing", "public/index.html"} {
+		if _, err := NewE(fsys, Options{Root: root}); err == nil || !strings.Contains(err.Error(), "invalid static root '"+root+"'") {
+			t.Errorf("%s: got error %v", root, err)
+		}
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("New didn't panic with an invalid root")
+		}
+	}()
+	New(fsys, Options{Root: "missing"})
+}
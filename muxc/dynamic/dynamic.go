@@ -0,0 +1,214 @@
+// Package dynamic builds a mux at runtime from a muxc yaml configuration, without generating
+// code. Handler, middleware, var and arg expressions are evaluated against a Registry that the
+// application populates with the functions and values they reference:
+//
+//	dynamic.Register("handlers.ListPets", handlers.ListPets)
+//	dynamic.Register("ctrl", controllers.NewController())
+//	mux, err := dynamic.Load("muxc.yaml", nil)
+//
+// Expressions are limited to names, selectors, function calls and basic literals, macros are
+// expanded and typed wildcards are validated as in generated code. The features implemented by the
+// packages of the middlewares module, which is not a dependency of the runtime mode, are not
+// supported:
+//
+//   - route descriptors are not injected into the request context, so route.GetRoute returns nil
+//   - static, proxy and redirect routes are rejected
+//   - routes with a timeout, maxBodyBytes, readDeadline or writeDeadline (declared or inherited)
+//     are rejected
+//
+// A configuration using them, like the one of the basic example, can't be loaded.
+package dynamic
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/enolgor/muxc/muxc/generator"
+)
+
+// InvalidWildcard is called when a typed wildcard does not match its declared type, as in
+// generated code it can be replaced to customize the response.
+var InvalidWildcard = func(w http.ResponseWriter, req *http.Request, name string, value string) {
+	http.Error(w, "invalid value '"+value+"' for path wildcard '"+name+"'", http.StatusBadRequest)
+}
+
+// Load reads a yaml configuration file and its includes from disk and returns a mux serving its
+// routes. A nil registry means the DefaultRegistry. Routes restricted to profiles are only
+// registered if one of their profiles is in the given ones.
+func Load(name string, registry *Registry, profiles ...string) (*http.ServeMux, error) {
+	yamlFile, err := generator.LoadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("error loading %s: %w", name, err)
+	}
+	mux := http.NewServeMux()
+	if err := Configure(mux, yamlFile, registry, profiles...); err != nil {
+		return nil, err
+	}
+	return mux, nil
+}
+
+// LoadFS is like Load but reads the yaml configuration file and its includes from fsys.
+func LoadFS(fsys fs.FS, name string, registry *Registry, profiles ...string) (*http.ServeMux, error) {
+	yamlFile, err := generator.Load(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("error loading %s: %w", name, err)
+	}
+	mux := http.NewServeMux()
+	if err := Configure(mux, yamlFile, registry, profiles...); err != nil {
+		return nil, err
+	}
+	return mux, nil
+}
+
+// Configure registers the routes of a yaml configuration in mux. Every expression is evaluated
+// before registering any route, so an unknown name is reported without modifying the mux. Panics
+// of the registered functions and of the mux are returned as errors.
+func Configure(mux *http.ServeMux, yamlFile *generator.MultiYamlFile, registry *Registry, profiles ...string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("error configuring the routes: %v", r)
+		}
+	}()
+	if registry == nil {
+		registry = DefaultRegistry
+	}
+	cfg, _, err := generator.ParseRoutes(yamlFile)
+	if err != nil {
+		return err
+	}
+	s := &scope{registry: registry, locals: map[string]reflect.Value{}}
+	for _, arg := range cfg.Args {
+		value, ok := registry.lookup(arg.Name)
+		if !ok {
+			return fmt.Errorf("arg '%s' is not registered", arg.Name)
+		}
+		s.locals[arg.Name] = value
+	}
+	for _, v := range cfg.OrderedVars {
+		if s.locals[v.Name], err = s.evaluate(v.Expr); err != nil {
+			return fmt.Errorf("error evaluating var '%s': %w", v.Name, err)
+		}
+	}
+	type route struct {
+		pattern string
+		handler http.HandlerFunc
+	}
+	routes := []route{}
+	for i := range cfg.Routes {
+		for _, path := range cfg.Routes[i].ParsedPaths {
+			if len(path.Profiles) > 0 && !slices.ContainsFunc(path.Profiles, func(profile string) bool { return slices.Contains(profiles, profile) }) {
+				continue
+			}
+			handler, err := build(s, path)
+			if err != nil {
+				return fmt.Errorf("%s: route '%s' %w", path.Position, path.MuxPattern, err)
+			}
+			routes = append(routes, route{pattern: path.MuxPattern, handler: handler})
+		}
+	}
+	for _, route := range routes {
+		if err := register(mux, route.pattern, route.handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// register reports the panics of the mux, like conflicting patterns, as errors.
+func register(mux *http.ServeMux, pattern string, handler http.HandlerFunc) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("route '%s' registration failed: %v", pattern, r)
+		}
+	}()
+	mux.Handle(pattern, handler)
+	return nil
+}
+
+// build evaluates the handler and middlewares of a route, returning the handler wrapped by
+// the wildcard validator and the middlewares (outermost first).
+func build(s *scope, path generator.ParsedPath) (http.HandlerFunc, error) {
+	// builtin routes are served by the static, proxy and redirect packages, which are not dependencies
+	// of the runtime mode
+	switch {
+	case path.Static != nil:
+		return nil, fmt.Errorf("is a static route, which is not supported in runtime mode")
+	case path.Proxy != nil:
+		return nil, fmt.Errorf("is a proxy route, which is not supported in runtime mode")
+	case path.Redirect != nil:
+		return nil, fmt.Errorf("is a redirect route, which is not supported in runtime mode")
+	}
+	// the limits are applied by the route package, which is not a dependency of the runtime mode
+	if len(path.Limits) > 0 {
+		name := path.Limits[0].Name
+		return nil, fmt.Errorf("%s is not supported in runtime mode", strings.ToLower(name[:1])+name[1:])
+	}
+	value, err := s.evaluate(path.Handler)
+	if err != nil {
+		return nil, fmt.Errorf("handler %s: %w", path.Handler, err)
+	}
+	handler, err := asHandler(value)
+	if err != nil {
+		return nil, fmt.Errorf("handler %s: %w", path.Handler, err)
+	}
+	if handler, err = validate(handler, path.Wildcards); err != nil {
+		return nil, err
+	}
+	for i := len(path.Stack) - 1; i >= 0; i-- {
+		value, err := s.evaluate(path.Stack[i])
+		if err != nil {
+			return nil, fmt.Errorf("middleware %s: %w", path.Stack[i], err)
+		}
+		middleware, err := asMiddleware(value)
+		if err != nil {
+			return nil, fmt.Errorf("middleware %s: %w", path.Stack[i], err)
+		}
+		if handler, err = wrap(middleware, handler); err != nil {
+			return nil, fmt.Errorf("middleware %s: %w", path.Stack[i], err)
+		}
+	}
+	return handler, nil
+}
+
+// wrap applies a middleware, reporting its panics as errors.
+func wrap(middleware func(http.HandlerFunc) http.HandlerFunc, next http.HandlerFunc) (handler http.HandlerFunc, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panicked: %v", r)
+		}
+	}()
+	return middleware(next), nil
+}
+
+func validate(next http.HandlerFunc, wildcards []generator.Wildcard) (http.HandlerFunc, error) {
+	type check struct {
+		name  string
+		valid func(string) bool
+	}
+	checks := []check{}
+	for _, wc := range wildcards {
+		valid, err := wc.Validator()
+		if err != nil {
+			return nil, err
+		}
+		if valid != nil {
+			checks = append(checks, check{wc.Name, valid})
+		}
+	}
+	if len(checks) == 0 {
+		return next, nil
+	}
+	return func(w http.ResponseWriter, req *http.Request) {
+		for _, c := range checks {
+			if value := req.PathValue(c.name); !c.valid(value) {
+				InvalidWildcard(w, req, c.name, value)
+				return
+			}
+		}
+		next(w, req)
+	}, nil
+}
@@ -0,0 +1,84 @@
+package dynamic
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadFS(t *testing.T) {
+	ok := func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("ok"))
+	}
+	registry := NewRegistry()
+	registry.Register("handlers.OK", ok)
+	registry.Register("handlers.Panic", func() http.HandlerFunc { panic("no handler") })
+	registry.Register("middlewares.Panic", func(next http.HandlerFunc) http.HandlerFunc { panic("no middleware") })
+	tests := []struct {
+		name     string
+		routes   string
+		err      string
+		requests map[string]int
+	}{
+		{
+			name:   "typed wildcards",
+			routes: "      - GET /pets/{id:int64} ;handlers.OK\n      - GET /users/{id:uuid} ;handlers.OK\n      - GET /tags/{tag:slug} ;handlers.OK\n      - GET /codes/{code:[A-Z]{2}} ;handlers.OK\n",
+			requests: map[string]int{
+				"/pets/12": http.StatusOK,
+				"/pets/a":  http.StatusBadRequest,
+				"/users/0b5e4c4a-3f2a-4b8e-9d1c-6f7a8b9c0d1e": http.StatusOK,
+				"/users/1":   http.StatusBadRequest,
+				"/tags/a-b":  http.StatusOK,
+				"/tags/A_B":  http.StatusBadRequest,
+				"/codes/ES":  http.StatusOK,
+				"/codes/ESP": http.StatusBadRequest,
+			},
+		},
+		{
+			name:   "panicking handler",
+			routes: "      - GET /pets ;handlers.Panic()\n",
+			err:    "route 'GET /pets' handler handlers.Panic(): 'handlers.Panic()' panicked: no handler",
+		},
+		{
+			name:   "panicking middleware",
+			routes: "      - GET /pets ;handlers.OK ;middlewares.Panic\n",
+			err:    "route 'GET /pets' middleware middlewares.Panic: panicked: no middleware",
+		},
+		{
+			name:   "conflicting patterns",
+			routes: "      - GET /pets/{id} ;handlers.OK\n      - GET /pets/{name} ;handlers.OK\n",
+			err:    "conflicts with pattern",
+		},
+		{
+			name:   "timeout",
+			routes: "      - path: GET /pets ;handlers.OK\n        timeout: 1s\n",
+			err:    "timeout is not supported in runtime mode",
+		},
+		{
+			name:   "redirect",
+			routes: "      - path: GET /pets\n        redirect:\n          to: /animals\n",
+			err:    "is a redirect route, which is not supported in runtime mode",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fsys := fstest.MapFS{"muxc.yaml": {Data: []byte("package: routes\nout: .\nroutes:\n  - paths:\n" + test.routes)}}
+			mux, err := LoadFS(fsys, "muxc.yaml", registry)
+			switch {
+			case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+				t.Fatalf("got error %v, want %q", err, test.err)
+			case test.err == "" && err != nil:
+				t.Fatal(err)
+			}
+			for path, status := range test.requests {
+				rec := httptest.NewRecorder()
+				mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+				if rec.Code != status {
+					t.Errorf("%s: got status %d, want %d", path, rec.Code, status)
+				}
+			}
+		})
+	}
+}
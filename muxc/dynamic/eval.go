@@ -0,0 +1,256 @@
+package dynamic
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+var (
+	errorType      = reflect.TypeOf((*error)(nil)).Elem()
+	handlerType    = reflect.TypeOf((*http.Handler)(nil)).Elem()
+	handlerFunc    = reflect.TypeOf(http.HandlerFunc(nil))
+	middlewareType = reflect.TypeOf((func(http.HandlerFunc) http.HandlerFunc)(nil))
+	wrapperType    = reflect.TypeOf((func(http.Handler) http.Handler)(nil))
+)
+
+// builtins are the helpers declared by the generated routes file.
+var builtins = map[string]reflect.Value{
+	"Middleware": reflect.ValueOf(func(m func(http.Handler) http.Handler) func(http.HandlerFunc) http.HandlerFunc {
+		return func(next http.HandlerFunc) http.HandlerFunc {
+			return m(next).ServeHTTP
+		}
+	}),
+	"stack": reflect.ValueOf(func(mws ...func(http.HandlerFunc) http.HandlerFunc) func(http.HandlerFunc) http.HandlerFunc {
+		return func(f http.HandlerFunc) http.HandlerFunc {
+			for _, m := range mws {
+				f = m(f)
+			}
+			return f
+		}
+	}),
+}
+
+// scope resolves names, first against the args and vars, then against the registry and
+// finally against the builtins.
+type scope struct {
+	registry *Registry
+	locals   map[string]reflect.Value
+}
+
+func (s *scope) resolve(name string) (reflect.Value, bool) {
+	if value, ok := s.locals[name]; ok {
+		return value, true
+	}
+	if value, ok := s.registry.lookup(name); ok {
+		return value, true
+	}
+	value, ok := builtins[name]
+	return value, ok
+}
+
+// evaluate parses and evaluates a go expression. Only names, selectors, function calls and
+// basic literals are supported. Panics of the called functions are returned as errors.
+func (s *scope) evaluate(expr string) (value reflect.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			value, err = reflect.Value{}, fmt.Errorf("'%s' panicked: %v", expr, r)
+		}
+	}()
+	node, err := parser.ParseExpr(expr)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("error parsing expression '%s': %w", expr, err)
+	}
+	return s.eval(node)
+}
+
+func (s *scope) eval(node ast.Expr) (reflect.Value, error) {
+	switch node := node.(type) {
+	case *ast.ParenExpr:
+		return s.eval(node.X)
+	case *ast.BasicLit:
+		return literal(node)
+	case *ast.Ident:
+		switch node.Name {
+		case "true", "false":
+			return reflect.ValueOf(node.Name == "true"), nil
+		case "nil":
+			return reflect.Value{}, nil
+		}
+		if value, ok := s.resolve(node.Name); ok {
+			return value, nil
+		}
+		return reflect.Value{}, fmt.Errorf("unknown name '%s'", node.Name)
+	case *ast.SelectorExpr:
+		name, dotted := dottedName(node)
+		if value, ok := s.resolve(name); dotted && ok {
+			return value, nil
+		}
+		x, err := s.eval(node.X)
+		if err != nil {
+			if dotted {
+				return reflect.Value{}, fmt.Errorf("unknown name '%s'", name)
+			}
+			return reflect.Value{}, err
+		}
+		return selectMember(x, node.Sel.Name, types.ExprString(node))
+	case *ast.CallExpr:
+		fn, err := s.eval(node.Fun)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		args := make([]reflect.Value, len(node.Args))
+		for i := range node.Args {
+			if args[i], err = s.eval(node.Args[i]); err != nil {
+				return reflect.Value{}, err
+			}
+		}
+		return call(fn, args, node.Ellipsis.IsValid(), types.ExprString(node))
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported expression '%s'", types.ExprString(node))
+}
+
+// dottedName returns names like handlers.ListPets for selector expressions made of identifiers.
+func dottedName(node ast.Expr) (string, bool) {
+	switch node := node.(type) {
+	case *ast.Ident:
+		return node.Name, true
+	case *ast.SelectorExpr:
+		x, ok := dottedName(node.X)
+		return x + "." + node.Sel.Name, ok
+	}
+	return "", false
+}
+
+func literal(lit *ast.BasicLit) (reflect.Value, error) {
+	switch lit.Kind {
+	case token.INT:
+		value, err := strconv.ParseInt(lit.Value, 0, 0)
+		return reflect.ValueOf(int(value)), err
+	case token.FLOAT:
+		value, err := strconv.ParseFloat(lit.Value, 64)
+		return reflect.ValueOf(value), err
+	case token.STRING:
+		value, err := strconv.Unquote(lit.Value)
+		return reflect.ValueOf(value), err
+	case token.CHAR:
+		value, _, _, err := strconv.UnquoteChar(lit.Value[1:len(lit.Value)-1], '\'')
+		return reflect.ValueOf(value), err
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported literal %s", lit.Value)
+}
+
+func selectMember(x reflect.Value, name string, expr string) (reflect.Value, error) {
+	if x.IsValid() {
+		if method := x.MethodByName(name); method.IsValid() {
+			return method, nil
+		}
+		if elem := reflect.Indirect(x); elem.Kind() == reflect.Struct {
+			if field := elem.FieldByName(name); field.IsValid() && field.CanInterface() {
+				return field, nil
+			}
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("unknown name '%s'", expr)
+}
+
+func call(fn reflect.Value, args []reflect.Value, ellipsis bool, expr string) (reflect.Value, error) {
+	if !fn.IsValid() || fn.Kind() != reflect.Func {
+		return reflect.Value{}, fmt.Errorf("'%s' calls a value that is not a function", expr)
+	}
+	if fn.IsNil() {
+		return reflect.Value{}, fmt.Errorf("'%s' calls a nil function", expr)
+	}
+	typ := fn.Type()
+	if ellipsis && !typ.IsVariadic() {
+		return reflect.Value{}, fmt.Errorf("'%s' uses ... with a function that is not variadic", expr)
+	}
+	if !typ.IsVariadic() && len(args) != typ.NumIn() || typ.IsVariadic() && len(args) < typ.NumIn()-1 || ellipsis && len(args) != typ.NumIn() {
+		return reflect.Value{}, fmt.Errorf("'%s' has %d arguments, the function expects %d", expr, len(args), typ.NumIn())
+	}
+	for i := range args {
+		param := typ.In(min(i, typ.NumIn()-1))
+		if typ.IsVariadic() && i >= typ.NumIn()-1 && !ellipsis {
+			param = param.Elem()
+		}
+		arg, err := assign(args[i], param)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("argument %d of '%s': %w", i+1, expr, err)
+		}
+		args[i] = arg
+	}
+	var results []reflect.Value
+	if ellipsis && typ.IsVariadic() {
+		results = fn.CallSlice(args)
+	} else {
+		results = fn.Call(args)
+	}
+	switch {
+	case len(results) == 1:
+		return results[0], nil
+	case len(results) == 2 && typ.Out(1) == errorType:
+		if !results[1].IsNil() {
+			return reflect.Value{}, fmt.Errorf("'%s' returned an error: %w", expr, results[1].Interface().(error))
+		}
+		return results[0], nil
+	}
+	return reflect.Value{}, fmt.Errorf("'%s' should return a single value (and optionally an error)", expr)
+}
+
+// assign converts a value to the type of a parameter, as the go compiler would for
+// assignable values, untyped constants and functions with the same signature.
+func assign(value reflect.Value, typ reflect.Type) (reflect.Value, error) {
+	if !value.IsValid() {
+		switch typ.Kind() {
+		case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice:
+			return reflect.Zero(typ), nil
+		}
+		return reflect.Value{}, fmt.Errorf("cannot use nil as %s", typ)
+	}
+	if value.Type().AssignableTo(typ) {
+		return value, nil
+	}
+	if value.Type().ConvertibleTo(typ) && (value.Kind() == typ.Kind() || isNumber(value.Kind()) && isNumber(typ.Kind())) {
+		return value.Convert(typ), nil
+	}
+	return reflect.Value{}, fmt.Errorf("cannot use %s as %s", value.Type(), typ)
+}
+
+func isNumber(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
+
+func asHandler(value reflect.Value) (http.HandlerFunc, error) {
+	if !value.IsValid() || (value.Kind() == reflect.Func || value.Kind() == reflect.Interface || value.Kind() == reflect.Pointer) && value.IsNil() {
+		return nil, errors.New("handler is nil")
+	}
+	if value.Type().ConvertibleTo(handlerFunc) && value.Kind() == reflect.Func {
+		return value.Convert(handlerFunc).Interface().(http.HandlerFunc), nil
+	}
+	if value.Type().Implements(handlerType) {
+		return value.Interface().(http.Handler).ServeHTTP, nil
+	}
+	return nil, fmt.Errorf("%s is not an http handler", value.Type())
+}
+
+func asMiddleware(value reflect.Value) (func(http.HandlerFunc) http.HandlerFunc, error) {
+	if !value.IsValid() || value.Kind() == reflect.Func && value.IsNil() {
+		return nil, errors.New("middleware is nil")
+	}
+	if value.Type().ConvertibleTo(middlewareType) {
+		return value.Convert(middlewareType).Interface().(func(http.HandlerFunc) http.HandlerFunc), nil
+	}
+	if value.Type().ConvertibleTo(wrapperType) {
+		m := value.Convert(wrapperType).Interface().(func(http.Handler) http.Handler)
+		return func(next http.HandlerFunc) http.HandlerFunc {
+			return m(next).ServeHTTP
+		}, nil
+	}
+	return nil, fmt.Errorf("%s is not a middleware, it should be a func(http.HandlerFunc) http.HandlerFunc", value.Type())
+}
@@ -0,0 +1,150 @@
+package dynamic
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testCtrl struct {
+	Name string
+}
+
+func (c *testCtrl) Hello(greeting string) string {
+	return greeting + " " + c.Name
+}
+
+func TestEvaluate(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("join", func(sep string, parts ...string) string { return strings.Join(parts, sep) })
+	registry.Register("parts", []string{"a", "b"})
+	registry.Register("pair", func(a, b string) string { return a + b })
+	registry.Register("first", func(parts []string) string { return parts[0] })
+	registry.Register("seconds", func(n int64) string { return fmt.Sprint(n) })
+	registry.Register("ratio", func(f float64) string { return fmt.Sprint(f) })
+	registry.Register("orDefault", func(f func() string) string {
+		if f == nil {
+			return "default"
+		}
+		return f()
+	})
+	registry.Register("fail", func(fail bool) (string, error) {
+		if fail {
+			return "", errors.New("failed")
+		}
+		return "ok", nil
+	})
+	registry.Register("both", func() (string, string) { return "a", "b" })
+	registry.Register("ctrl", &testCtrl{Name: "ctrl"})
+	registry.Register("handlers.Name", "handlers")
+	tests := []struct {
+		expr string
+		want any
+		err  string
+	}{
+		{expr: `"text"`, want: "text"},
+		{expr: "'x'", want: 'x'},
+		{expr: "true", want: true},
+		{expr: "(1.5)", want: 1.5},
+		{expr: "handlers.Name", want: "handlers"},
+		// variadic functions, with and without ...
+		{expr: `join(",")`, want: ""},
+		{expr: `join(",", "a")`, want: "a"},
+		{expr: `join(",", "a", "b")`, want: "a,b"},
+		{expr: `join(",", parts...)`, want: "a,b"},
+		{expr: `join(",", "a", parts...)`, err: "has 3 arguments, the function expects 2"},
+		{expr: "join()", err: "has 0 arguments, the function expects 2"},
+		{expr: `join(",", 1)`, err: "argument 2 of 'join(\",\", 1)': cannot use int as string"},
+		// non variadic functions
+		{expr: `pair("a", "b")`, want: "ab"},
+		{expr: "first(parts)", want: "a"},
+		{expr: "first(parts...)", err: "uses ... with a function that is not variadic"},
+		{expr: `pair(parts...)`, err: "uses ... with a function that is not variadic"},
+		{expr: `pair("a", parts...)`, err: "uses ... with a function that is not variadic"},
+		{expr: `pair("a")`, err: "has 1 arguments, the function expects 2"},
+		// untyped constants and nil
+		{expr: "seconds(5)", want: "5"},
+		{expr: "ratio(2)", want: "2"},
+		{expr: "orDefault(nil)", want: "default"},
+		{expr: "seconds(nil)", err: "cannot use nil as int64"},
+		// errors and results
+		{expr: "fail(false)", want: "ok"},
+		{expr: "fail(true)", err: "'fail(true)' returned an error: failed"},
+		{expr: "both()", err: "should return a single value"},
+		// methods and fields
+		{expr: `ctrl.Hello("hi")`, want: "hi ctrl"},
+		{expr: "ctrl.Name", want: "ctrl"},
+		{expr: "ctrl.Missing", err: "unknown name 'ctrl.Missing'"},
+		{expr: "missing.Name", err: "unknown name 'missing.Name'"},
+		{expr: "missing", err: "unknown name 'missing'"},
+		{expr: `ctrl.Name("x")`, err: "calls a value that is not a function"},
+		{expr: "parts[0]", err: "unsupported expression 'parts[0]'"},
+	}
+	s := &scope{registry: registry}
+	for _, test := range tests {
+		got, err := s.evaluate(test.expr)
+		switch {
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%s: got error %v, want %q", test.expr, err, test.err)
+		case test.err == "" && err != nil:
+			t.Errorf("%s: %v", test.expr, err)
+		case test.err == "" && !reflect.DeepEqual(got.Interface(), test.want):
+			t.Errorf("%s: got %v, want %v", test.expr, got, test.want)
+		}
+	}
+}
+
+func TestEvaluateMiddlewares(t *testing.T) {
+	header := func(value string) func(http.HandlerFunc) http.HandlerFunc {
+		return func(next http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Add("X-Test", value)
+				next(w, req)
+			}
+		}
+	}
+	wrapper := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Add("X-Test", "wrapper")
+			next.ServeHTTP(w, req)
+		})
+	}
+	registry := NewRegistry()
+	registry.Register("header", header)
+	registry.Register("wrapper", wrapper)
+	registry.Register("headers", []func(http.HandlerFunc) http.HandlerFunc{header("a"), header("b")})
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{expr: `header("a")`, want: []string{"a"}},
+		{expr: "wrapper", want: []string{"wrapper"}},
+		{expr: "Middleware(wrapper)", want: []string{"wrapper"}},
+		{expr: `stack(header("a"), header("b"))`, want: []string{"b", "a"}},
+		{expr: "stack(headers...)", want: []string{"b", "a"}},
+		{expr: `stack(Middleware(wrapper), header("a"))`, want: []string{"a", "wrapper"}},
+		{expr: "stack()", want: nil},
+	}
+	s := &scope{registry: registry}
+	for _, test := range tests {
+		value, err := s.evaluate(test.expr)
+		if err != nil {
+			t.Errorf("%s: %v", test.expr, err)
+			continue
+		}
+		m, err := asMiddleware(value)
+		if err != nil {
+			t.Errorf("%s: %v", test.expr, err)
+			continue
+		}
+		rec := httptest.NewRecorder()
+		m(func(w http.ResponseWriter, req *http.Request) {})(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if got := rec.Header().Values("X-Test"); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got headers %q, want %q", test.expr, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package dynamic
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Registry holds the values that handler, middleware, var and arg expressions are resolved
+// against, by the name used in the yaml configuration (e.g. handlers.ListPets or ctrl).
+type Registry struct {
+	mu     sync.RWMutex
+	values map[string]reflect.Value
+}
+
+func NewRegistry() *Registry {
+	return &Registry{values: map[string]reflect.Value{}}
+}
+
+// DefaultRegistry is the registry used when loading a configuration with a nil registry.
+var DefaultRegistry = NewRegistry()
+
+// Register adds a value (typically a function) to the registry under name, replacing any
+// value previously registered with the same name.
+func (r *Registry) Register(name string, value any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values[name] = reflect.ValueOf(value)
+}
+
+func (r *Registry) lookup(name string) (reflect.Value, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	value, ok := r.values[name]
+	return value, ok
+}
+
+// Register adds a value to the DefaultRegistry.
+func Register(name string, value any) {
+	DefaultRegistry.Register(name, value)
+}
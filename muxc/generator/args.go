@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"slices"
+)
+
+const (
+	ArgsPositional string = "positional"
+	ArgsOptions    string = "options"
+	ArgsFunctional string = "functional"
+)
+
+type Param struct {
+	Name  string
+	Type  string
+	Field string
+	Used  bool
+}
+
+// paramReserved are the identifiers declared by the generated ConfigureMux for each args style.
+var paramReserved = map[string][]string{
+	ArgsPositional: {"mux"},
+	ArgsOptions:    {"mux", "opts"},
+	ArgsFunctional: {"mux", "opts", "opt", "o", "options", "Option"},
+}
+
+// prepareParams validates the args and, unless they are passed positionally, marks the ones
+// referenced by vars or routes, as only those are copied to local variables from the options.
+func prepareParams(cfg *Conf) error {
+	if cfg.ArgsStyle == "" {
+		cfg.ArgsStyle = ArgsPositional
+	}
+	reserved, ok := paramReserved[cfg.ArgsStyle]
+	if !ok {
+		return fmt.Errorf("invalid argsStyle '%s', it should be one of %s, %s or %s", cfg.ArgsStyle, ArgsPositional, ArgsOptions, ArgsFunctional)
+	}
+	exprs := []string{}
+	for _, decl := range cfg.OrderedVars {
+		exprs = append(exprs, decl.Expr)
+	}
+	for i := range cfg.Routes {
+		for _, path := range cfg.Routes[i].ParsedPaths {
+			exprs = append(append(exprs, path.Handler), path.Stack...)
+		}
+	}
+	refs := []string{}
+	for _, expr := range exprs {
+		found, err := identifiers(expr)
+		if err != nil {
+			return fmt.Errorf("error parsing expression '%s': %w", expr, err)
+		}
+		refs = append(refs, found...)
+	}
+	cfg.routesFile.Params = make([]Param, len(cfg.Args))
+	fields := map[string]string{}
+	for i, arg := range cfg.Args {
+		if !isIdentifier(arg.Name) || token.IsKeyword(arg.Name) || slices.Contains(reserved, arg.Name) {
+			return fmt.Errorf("invalid arg name '%s'", arg.Name)
+		}
+		field := goName(arg.Name)
+		if cfg.ArgsStyle != ArgsPositional {
+			if field == "" {
+				return fmt.Errorf("unable to derive an option name for arg '%s'", arg.Name)
+			}
+			if other, exists := fields[field]; exists {
+				return fmt.Errorf("args '%s' and '%s' would both be named %s", other, arg.Name, field)
+			}
+			fields[field] = arg.Name
+		}
+		cfg.routesFile.Params[i] = Param{Name: arg.Name, Type: arg.Expr, Field: field, Used: slices.Contains(refs, arg.Name)}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPrepareParams(t *testing.T) {
+	const routes = "routes:\n  - paths:\n      - GET /pets ;handlers.List(store, limit)\n"
+	tests := []struct {
+		name   string
+		config string
+		want   []Param
+		err    string
+	}{
+		{
+			name:   "declaration order",
+			config: "args:\n  store: map[string]int\n  limit: int\n  check: func(string) error\n" + routes,
+			want: []Param{
+				{Name: "store", Type: "map[string]int", Field: "Store", Used: true},
+				{Name: "limit", Type: "int", Field: "Limit", Used: true},
+				{Name: "check", Type: "func(string) error", Field: "Check"},
+			},
+		},
+		{
+			name:   "sequence of mappings",
+			config: "args:\n  - limit: int\n  - store: map[string]int\n" + routes,
+			want: []Param{
+				{Name: "limit", Type: "int", Field: "Limit", Used: true},
+				{Name: "store", Type: "map[string]int", Field: "Store", Used: true},
+			},
+		},
+		{
+			name:   "used by vars",
+			config: "argsStyle: options\nargs:\n  store: map[string]int\n  limit: int\n  ids: '[]string'\nvars:\n  all: handlers.All(ids)\n" + "routes:\n  - paths:\n      - GET /pets ;handlers.List(store, limit) ;all\n",
+			want: []Param{
+				{Name: "store", Type: "map[string]int", Field: "Store", Used: true},
+				{Name: "limit", Type: "int", Field: "Limit", Used: true},
+				{Name: "ids", Type: "[]string", Field: "Ids", Used: true},
+			},
+		},
+		{
+			name:   "invalid style",
+			config: "argsStyle: named\n" + routes,
+			err:    "invalid argsStyle 'named', it should be one of positional, options or functional",
+		},
+		{
+			name:   "keyword",
+			config: "args:\n  type: string\n" + routes,
+			err:    "invalid arg name 'type'",
+		},
+		{
+			name:   "reserved by the style",
+			config: "argsStyle: functional\nargs:\n  opt: string\n" + routes,
+			err:    "invalid arg name 'opt'",
+		},
+		{
+			name:   "reserved by another style",
+			config: "args:\n  opt: string\n" + routes,
+			want:   []Param{{Name: "opt", Type: "string", Field: "Opt"}},
+		},
+		{
+			name:   "same option name",
+			config: "argsStyle: options\nargs:\n  api_url: string\n  _api_url: string\n" + routes,
+			err:    "args 'api_url' and '_api_url' would both be named APIURL",
+		},
+		{
+			name:   "without option name",
+			config: "argsStyle: functional\nargs:\n  __: string\n" + routes,
+			err:    "unable to derive an option name for arg '__'",
+		},
+		{
+			name:   "duplicated",
+			config: "args:\n  - limit: int\n  - limit: int\n" + routes,
+			err:    "'limit' is declared more than once",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg, err := parseTest(t, test.config)
+			switch {
+			case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+				t.Errorf("got error %v, want %q", err, test.err)
+			case test.err == "" && err != nil:
+				t.Error(err)
+			case test.err == "" && !reflect.DeepEqual(cfg.routesFile.Params, test.want):
+				t.Errorf("got params %+v, want %+v", cfg.routesFile.Params, test.want)
+			}
+		})
+	}
+}
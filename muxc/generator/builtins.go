@@ -0,0 +1,304 @@
+package generator
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	staticPackage   string = "github.com/enolgor/muxc/middlewares/static"
+	proxyPackage    string = "github.com/enolgor/muxc/middlewares/proxy"
+	redirectPackage string = "github.com/enolgor/muxc/middlewares/redirect"
+)
+
+// StaticConf declares a route serving the files of a directory or of a fs.FS expression
+// (e.g. an embed.FS var) under the route pattern, which is the mount prefix.
+type StaticConf struct {
+	Dir           string `yaml:"dir"`
+	FS            string `yaml:"fs"`
+	Root          string `yaml:"root"`
+	SPA           bool   `yaml:"spa"`
+	CacheControl  string `yaml:"cacheControl"`
+	Precompressed bool   `yaml:"precompressed"`
+}
+
+// ProxyConf declares a route forwarding requests to the url of the target expression (e.g. an arg
+// or var) with a reverse proxy, optionally rewriting the path with a template of the route wildcards.
+type ProxyConf struct {
+	Target  string            `yaml:"target"`
+	Path    string            `yaml:"path"`
+	Headers map[string]string `yaml:"headers"`
+	Timeout string            `yaml:"timeout"`
+	timeout time.Duration
+}
+
+// RedirectConf declares a route redirecting requests to a template of the route wildcards.
+type RedirectConf struct {
+	To     string `yaml:"to"`
+	Status int    `yaml:"status"`
+}
+
+// builtin reports whether the handler of the path is generated from a static, proxy or redirect route.
+func (path ParsedPath) builtin() bool {
+	return path.Static != nil || path.Proxy != nil || path.Redirect != nil
+}
+
+// parseBuiltin validates the static, proxy or redirect route of a path, appending the remainder
+// wildcard matching the file names to the pattern of static routes.
+func (parsed *ParsedPath) parseBuiltin(rp RoutePath) (err error) {
+	declared := 0
+	for _, builtin := range []bool{rp.Static != nil, rp.Proxy != nil, rp.Redirect != nil} {
+		if builtin {
+			declared++
+		}
+	}
+	if declared > 1 {
+		return fmt.Errorf("route '%s' should have only one of static, proxy or redirect", parsed.Pattern)
+	}
+	if parsed.Handler != "" {
+		return fmt.Errorf("%s route '%s' can't have a handler", parsed.builtinKind(rp), parsed.Pattern)
+	}
+	parsed.Static, parsed.Proxy, parsed.Redirect = rp.Static, rp.Proxy, rp.Redirect
+	switch {
+	case rp.Static != nil:
+		return parsed.parseStatic(rp.Static)
+	case rp.Proxy != nil:
+		if rp.Proxy.Target == "" {
+			return fmt.Errorf("proxy route '%s' should have a target", parsed.Pattern)
+		}
+		if rp.Proxy.Timeout != "" {
+			if rp.Proxy.timeout, err = time.ParseDuration(rp.Proxy.Timeout); err != nil || rp.Proxy.timeout <= 0 {
+				return fmt.Errorf("invalid proxy timeout '%s', it should be a positive duration (e.g. 5s)", rp.Proxy.Timeout)
+			}
+		}
+	case rp.Redirect != nil:
+		if rp.Redirect.To == "" {
+			return fmt.Errorf("redirect route '%s' should have a target (to)", parsed.Pattern)
+		}
+		if rp.Redirect.Status == 0 {
+			rp.Redirect.Status = http.StatusMovedPermanently
+		}
+		if rp.Redirect.Status < 300 || rp.Redirect.Status > 399 {
+			return fmt.Errorf("invalid redirect status %d, it should be a 3xx status", rp.Redirect.Status)
+		}
+	}
+	return nil
+}
+
+func (parsed *ParsedPath) builtinKind(rp RoutePath) string {
+	switch {
+	case rp.Static != nil:
+		return "static"
+	case rp.Proxy != nil:
+		return "proxy"
+	}
+	return "redirect"
+}
+
+func (parsed *ParsedPath) parseStatic(static *StaticConf) (err error) {
+	if len(parsed.Wildcards) > 0 || !strings.HasSuffix(parsed.Pattern, "/") {
+		return fmt.Errorf("static route pattern '%s' should be a prefix ending with / and without wildcards", parsed.Pattern)
+	}
+	if (static.Dir == "") == (static.FS == "") {
+		return fmt.Errorf("static route '%s' should have either dir or fs", parsed.Pattern)
+	}
+	if static.Root != "" && !fs.ValidPath(static.Root) {
+		return fmt.Errorf("invalid static root '%s', it should be an unrooted slash separated path", static.Root)
+	}
+	parsed.Pattern, parsed.Wildcards, err = parsePattern(parsed.Pattern + "{path...}")
+	return
+}
+
+// checkTemplate verifies that the wildcards of a proxy path or redirect target are wildcards of the route.
+func checkTemplate(template string, wildcards []Wildcard) error {
+	for {
+		start := strings.Index(template, "{")
+		if start == -1 {
+			return nil
+		}
+		end := strings.Index(template[start:], "}") + start
+		if end < start {
+			return fmt.Errorf("unclosed wildcard in template '%s'", template)
+		}
+		name := strings.TrimSuffix(template[start+1:end], "...")
+		found := false
+		for _, wc := range wildcards {
+			found = found || wc.Name == name
+		}
+		if !found {
+			return fmt.Errorf("wildcard '%s' of template '%s' is not a wildcard of the route", name, template)
+		}
+		template = template[end+1:]
+	}
+}
+
+// handlerRefs returns the expressions whose packages are referenced by the handler of the path, for
+// builtin routes the packages of the generated handlers are imported as generated packages instead.
+func (path ParsedPath) handlerRefs() []string {
+	switch {
+	case path.Static != nil:
+		return []string{path.Static.fsExpr()}
+	case path.Proxy != nil && path.Proxy.timeout > 0:
+		return []string{path.Proxy.Target, durationExpr(path.Proxy.timeout)}
+	case path.Proxy != nil:
+		return []string{path.Proxy.Target}
+	case path.Redirect != nil:
+		return []string{}
+	}
+	return []string{path.Handler}
+}
+
+// fsExpr returns the expression of the file system served by a static route.
+func (static *StaticConf) fsExpr() string {
+	if static.Dir != "" {
+		return "os.DirFS(" + strconv.Quote(static.Dir) + ")"
+	}
+	return static.FS
+}
+
+// builtinHandler returns the handler expression of a static, proxy or redirect route.
+func builtinHandler(path ParsedPath) (string, error) {
+	switch {
+	case path.Static != nil:
+		return staticHandler(path.Static, path.FullPattern), nil
+	case path.Proxy != nil:
+		if err := checkTemplate(path.Proxy.Path, path.Wildcards); err != nil {
+			return "", err
+		}
+		return proxyHandler(path.Proxy, "New"), nil
+	default:
+		if err := checkTemplate(path.Redirect.To, path.Wildcards); err != nil {
+			return "", err
+		}
+		return "redirect.New(" + strconv.Quote(path.Redirect.To) + ", " + strconv.Itoa(path.Redirect.Status) + ")", nil
+	}
+}
+
+// proxyHandler returns the call of a constructor of the proxy package (New or NewE) building
+// the handler of a proxy route.
+func proxyHandler(proxy *ProxyConf, constructor string) string {
+	options := []string{}
+	if proxy.Path != "" {
+		options = append(options, "Path: "+strconv.Quote(proxy.Path))
+	}
+	if len(proxy.Headers) > 0 {
+		keys := make([]string, 0, len(proxy.Headers))
+		for key := range proxy.Headers {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		headers := make([]string, len(keys))
+		for i, key := range keys {
+			headers[i] = strconv.Quote(key) + ": " + strconv.Quote(proxy.Headers[key])
+		}
+		options = append(options, "Headers: map[string]string{"+strings.Join(headers, ", ")+"}")
+	}
+	if proxy.timeout > 0 {
+		options = append(options, "Timeout: "+durationExpr(proxy.timeout))
+	}
+	return "proxy." + constructor + "(" + proxy.Target + ", proxy.Options{" + strings.Join(options, ", ") + "})"
+}
+
+// prepareBuilders builds the handlers of the proxy routes and of the static routes with a root,
+// which fail to build if the target or root is invalid, with the constructors returning an error
+// when ConfigureMux returns errors. The handler of the route is then the result of the builder,
+// named built.
+func prepareBuilders(cfg *Conf) {
+	if !cfg.ReturnErrors {
+		return
+	}
+	for _, path := range allPaths(cfg) {
+		switch {
+		case path.Proxy != nil:
+			path.Builder, path.BuiltHandler = proxyHandler(path.Proxy, "NewE"), "built"
+		case path.Static != nil && path.Static.Root != "":
+			path.Builder, path.BuiltHandler = staticCall(path.Static, "NewE"), stripPrefix("built", path.FullPattern)
+		default:
+			continue
+		}
+		cfg.routesFile.Builders = true
+	}
+}
+
+// staticHandler returns the handler expression of a static route, stripping the mount prefix
+// of the full pattern from the request path.
+func staticHandler(static *StaticConf, fullPattern string) string {
+	return stripPrefix(staticCall(static, "New"), fullPattern)
+}
+
+// staticCall returns the call of a constructor of the static package (New or NewE) building
+// the handler of a static route.
+func staticCall(static *StaticConf, constructor string) string {
+	options := []string{}
+	if static.Root != "" {
+		options = append(options, "Root: "+strconv.Quote(static.Root))
+	}
+	if static.SPA {
+		options = append(options, "SPA: true")
+	}
+	if static.CacheControl != "" {
+		options = append(options, "CacheControl: "+strconv.Quote(static.CacheControl))
+	}
+	if static.Precompressed {
+		options = append(options, "Precompressed: true")
+	}
+	return "static." + constructor + "(" + static.fsExpr() + ", static.Options{" + strings.Join(options, ", ") + "})"
+}
+
+// stripPrefix wraps the handler of a static route to strip the mount prefix of its full pattern.
+func stripPrefix(handler string, fullPattern string) string {
+	prefix := strings.TrimSuffix(fullPattern[strings.Index(fullPattern, "/"):], "/{path...}")
+	if prefix == "" {
+		return handler
+	}
+	return "http.StripPrefix(" + strconv.Quote(prefix) + ", " + handler + ").ServeHTTP"
+}
+
+// durationExpr returns the go expression of a duration, e.g. 5 * time.Second.
+func durationExpr(d time.Duration) string {
+	units := []struct {
+		duration time.Duration
+		name     string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	}
+	for _, unit := range units {
+		if d%unit.duration == 0 {
+			if d == unit.duration {
+				return unit.name
+			}
+			return fmt.Sprintf("%d * %s", d/unit.duration, unit.name)
+		}
+	}
+	return fmt.Sprintf("time.Duration(%d)", d)
+}
+
+// addBuiltinImports adds the packages of the generated handlers of the static, proxy and redirect
+// routes registered by ConfigureMux.
+func addBuiltinImports(cfg *Conf) {
+	add := func(pkg string) {
+		if !slices.Contains(cfg.routesFile.UsedImports, strconv.Quote(pkg)) {
+			cfg.routesFile.UsedImports = append(cfg.routesFile.UsedImports, strconv.Quote(pkg))
+		}
+	}
+	for _, registration := range cfg.routesFile.Registrations {
+		switch path := registration.Path; {
+		case path.Static != nil:
+			add(staticPackage)
+		case path.Proxy != nil:
+			add(proxyPackage)
+		case path.Redirect != nil:
+			add(redirectPackage)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"path"
+	"slices"
+	"strings"
+)
+
+type ClientConf struct {
+	Package string `yaml:"package"`
+	Out     string `yaml:"out"`
+}
+
+type ClientFile struct {
+	Package     string
+	MuxcVersion string
+	SourceFile  string
+	StdImports  []string
+	Imports     []string
+	Methods     []ClientMethod
+}
+
+type ClientMethod struct {
+	Name   string
+	Method string
+	Params []ClientParam
+	Path   string
+	In     string
+	Out    string
+}
+
+type ClientParam struct {
+	Name string
+	Type string
+}
+
+// clientImports are the packages always imported by the generated client.
+var clientImports = []string{"bytes", "context", "encoding/json", "fmt", "io", "net/http", "net/url", "strings"}
+
+var clientReserved = []string{"c", "ctx", "method", "body", "out", "err", "url", "strconv", "escapePath"}
+
+// clientParamName avoids clashes between wildcard names and go keywords or the
+// identifiers used by the generated client methods.
+func clientParamName(name string) string {
+	if token.IsKeyword(name) || slices.Contains(clientReserved, name) {
+		return name + "Param"
+	}
+	return name
+}
+
+// qualifiers returns the package names referenced by a go type or expression,
+// e.g. []*controllers.Pet references controllers.
+func qualifiers(expr string) ([]string, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	node, err := parser.ParseExpr(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression '%s': %w", expr, err)
+	}
+	qualifiers := []string{}
+	ast.Inspect(node, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok && !slices.Contains(qualifiers, ident.Name) {
+				qualifiers = append(qualifiers, ident.Name)
+			}
+		}
+		return true
+	})
+	return qualifiers, nil
+}
+
+// importName returns the package name of an import path, assuming it matches the
+// last element of the path (ignoring major version suffixes).
+func importName(importPath string) string {
+	parts := strings.Split(importPath, "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = parts[len(parts)-2]
+	}
+	return name
+}
+
+func buildClientFile(cfg *Conf, basedir string) (*ClientFile, error) {
+	file := &ClientFile{
+		Package:     cfg.Client.Package,
+		MuxcVersion: cfg.MuxcVersion,
+		SourceFile:  cfg.SourceFile,
+		Imports:     []string{},
+		Methods:     []ClientMethod{},
+	}
+	refs := map[string]string{}
+	if err := checkRouteNames(cfg); err != nil {
+		return nil, err
+	}
+	for i := range cfg.Routes {
+		for _, path := range cfg.Routes[i].ParsedPaths {
+			if path.builtin() {
+				continue
+			}
+			method := ClientMethod{
+				Name:   path.RouteName(),
+				Method: path.Method,
+				Params: []ClientParam{},
+				In:     path.In,
+				Out:    path.Out,
+			}
+			params := map[string]ClientParam{}
+			for _, wc := range path.Wildcards {
+				param := ClientParam{Name: clientParamName(wc.Name), Type: wc.GoType()}
+				params[wc.Name] = param
+				method.Params = append(method.Params, param)
+				if param.Type != "string" && !slices.Contains(file.Imports, `"strconv"`) {
+					file.Imports = append(file.Imports, `"strconv"`)
+				}
+			}
+			method.Path = pathExpr(path.FullPattern, params)
+			for _, typ := range []string{path.In, path.Out} {
+				found, err := qualifiers(typ)
+				if err != nil {
+					return nil, fmt.Errorf("error parsing types of route '%s': %w", path.FullPattern, err)
+				}
+				for _, qualifier := range found {
+					if _, exists := refs[qualifier]; !exists {
+						refs[qualifier] = typ
+					}
+				}
+			}
+			file.Methods = append(file.Methods, method)
+		}
+	}
+	imports, err := resolveImports(cfg, basedir, refs, clientImports)
+	if err != nil {
+		return nil, err
+	}
+	file.StdImports, file.Imports = splitStdImports(append(file.Imports, imports...))
+	return file, nil
+}
+
+// RenderClient writes the typed client file to w.
+func (cfg *Conf) RenderClient(w io.Writer) error {
+	if cfg.clientFile == nil {
+		return fmt.Errorf("client is not configured")
+	}
+	return renderGo(w, "client.go.tmpl", cfg.clientFile)
+}
+
+func createClientFile(cfg *Conf, basedir string) error {
+	if cfg.Client.Out == "" {
+		return fmt.Errorf("client out directory is not configured")
+	}
+	return writeFile(path.Join(basedir, cfg.Client.Out), "client.go", cfg.RenderClient)
+}
@@ -0,0 +1,404 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+const postmanSchema string = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+type PostmanConf struct {
+	Out     string `yaml:"out"`
+	File    string `yaml:"file"`
+	Name    string `yaml:"name"`
+	BaseURL string `yaml:"baseURL"`
+}
+
+type HTTPFileConf struct {
+	Out     string `yaml:"out"`
+	File    string `yaml:"file"`
+	BaseURL string `yaml:"baseURL"`
+}
+
+// Collection is the model of the postman collection and the .http file, with a folder per route
+// group base and a variable per wildcard of each request.
+type Collection struct {
+	Name        string
+	MuxcVersion string
+	SourceFile  string
+	BaseURL     string
+	Folders     []CollectionFolder
+}
+
+type CollectionVariable struct {
+	Name  string
+	Value string
+}
+
+type CollectionFolder struct {
+	Name     string
+	Requests []CollectionRequest
+}
+
+type CollectionRequest struct {
+	Name        string
+	Description string
+	Method      string
+	Segments    []CollectionSegment
+	Variables   []CollectionVariable
+	Body        string
+	ContentType string
+}
+
+// CollectionSegment is a path segment, either a literal or the name of a wildcard.
+type CollectionSegment struct {
+	Literal  string
+	Wildcard string
+}
+
+// VariableName returns the name of the .http file variable of a wildcard, scoped by the request
+// name as file variables are global, e.g. readPet_id.
+func (req CollectionRequest) VariableName(wildcard string) string {
+	name := goName(req.Name)
+	upper := 0
+	for upper < len(name) && unicode.IsUpper(rune(name[upper])) {
+		upper++
+	}
+	// the first letter of the next word is kept, e.g. GETLegacy -> getLegacy
+	if upper > 1 && upper < len(name) {
+		upper--
+	}
+	return strings.ToLower(name[:upper]) + name[upper:] + "_" + wildcard
+}
+
+// URL returns the url of the request in the .http file syntax, e.g. {{baseUrl}}/pet/{{readPet_id}}.
+func (req CollectionRequest) URL() string {
+	url := "{{baseUrl}}"
+	for _, segment := range req.Segments {
+		if segment.Wildcard != "" {
+			url += "/{{" + req.VariableName(segment.Wildcard) + "}}"
+		} else {
+			url += "/" + segment.Literal
+		}
+	}
+	return url
+}
+
+// exampleBody returns an example body as JSON keeping the order of mapping keys,
+// strings are kept as they are.
+func exampleBody(node *yaml.Node) (string, error) {
+	if node.Kind == 0 {
+		return "", nil
+	}
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!str" {
+		return node.Value, nil
+	}
+	value, err := jsonValue(node)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// orderedObject is a JSON object keeping the order of its keys.
+type orderedObject struct {
+	keys   []string
+	values []any
+}
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	buffer.WriteByte('{')
+	for i := range o.keys {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		key, err := json.Marshal(o.keys[i])
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(o.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+func jsonValue(node *yaml.Node) (any, error) {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return jsonValue(node.Alias)
+	case yaml.MappingNode:
+		object := orderedObject{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value, err := jsonValue(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			object.keys = append(object.keys, node.Content[i].Value)
+			object.values = append(object.values, value)
+		}
+		return object, nil
+	case yaml.SequenceNode:
+		array := []any{}
+		for _, item := range node.Content {
+			value, err := jsonValue(item)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		return array, nil
+	}
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// exampleContentType returns the content type of an example body, JSON for the mappings,
+// sequences and scalars encoded as JSON and for strings holding JSON, text otherwise.
+func exampleContentType(body string) string {
+	if json.Valid([]byte(body)) {
+		return "application/json"
+	}
+	return "text/plain"
+}
+
+// wildcardExample returns a value matching the type of a wildcard, to use as variable default.
+func wildcardExample(wc Wildcard) string {
+	switch wc.Type {
+	case WildcardInt, WildcardInt64:
+		return "1"
+	case WildcardUUID:
+		return "00000000-0000-0000-0000-000000000000"
+	case WildcardSlug:
+		return "example"
+	}
+	return ""
+}
+
+// collectionSegments splits a pattern in segments, the host part of the pattern (if any) and {$} are dropped.
+func collectionSegments(pattern string) []CollectionSegment {
+	if i := strings.Index(pattern, "/"); i > 0 {
+		pattern = pattern[i:]
+	}
+	pattern = strings.TrimSuffix(pattern, "{$}")
+	segments := []CollectionSegment{}
+	for _, segment := range strings.Split(strings.TrimPrefix(pattern, "/"), "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments = append(segments, CollectionSegment{Wildcard: strings.TrimSuffix(segment[1:len(segment)-1], "...")})
+		} else {
+			segments = append(segments, CollectionSegment{Literal: segment})
+		}
+	}
+	return segments
+}
+
+func buildCollection(cfg *Conf, name string, baseURL string) *Collection {
+	if name == "" {
+		name = cfg.Package
+	}
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+	collection := &Collection{
+		Name:        name,
+		MuxcVersion: cfg.MuxcVersion,
+		SourceFile:  cfg.SourceFile,
+		BaseURL:     baseURL,
+		Folders:     []CollectionFolder{},
+	}
+	folders := map[string]int{}
+	for i := range cfg.Routes {
+		base := cfg.Routes[i].Base
+		if base == "" {
+			base = "/"
+		}
+		index, ok := folders[base]
+		if !ok {
+			index = len(collection.Folders)
+			folders[base] = index
+			collection.Folders = append(collection.Folders, CollectionFolder{Name: base, Requests: []CollectionRequest{}})
+		}
+		for _, path := range cfg.Routes[i].ParsedPaths {
+			request := CollectionRequest{
+				Name:        path.RouteName(),
+				Method:      path.Method,
+				Segments:    collectionSegments(path.FullPattern),
+				Variables:   []CollectionVariable{},
+				Body:        path.ExampleBody,
+				Description: path.Description,
+			}
+			if request.Name == "" {
+				request.Name = path.MuxPattern
+			}
+			if request.Method == "" {
+				request.Method = "GET"
+			}
+			if request.Body != "" {
+				request.ContentType = exampleContentType(request.Body)
+			}
+			for _, wc := range path.Wildcards {
+				request.Variables = append(request.Variables, CollectionVariable{Name: wc.Name, Value: wildcardExample(wc)})
+			}
+			collection.Folders[index].Requests = append(collection.Folders[index].Requests, request)
+		}
+	}
+	return collection
+}
+
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Variable []postmanVariable `json:"variable"`
+}
+
+type postmanInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Schema      string `json:"schema"`
+}
+
+type postmanItem struct {
+	Name    string          `json:"name"`
+	Item    []postmanItem   `json:"item,omitempty"`
+	Request *postmanRequest `json:"request,omitempty"`
+}
+
+type postmanRequest struct {
+	Method      string          `json:"method"`
+	Description string          `json:"description,omitempty"`
+	Header      []postmanHeader `json:"header"`
+	URL         postmanURL      `json:"url"`
+	Body        *postmanBody    `json:"body,omitempty"`
+}
+
+type postmanHeader struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type postmanURL struct {
+	Raw      string            `json:"raw"`
+	Host     []string          `json:"host"`
+	Path     []string          `json:"path"`
+	Variable []postmanVariable `json:"variable,omitempty"`
+}
+
+type postmanVariable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type postmanBody struct {
+	Mode    string `json:"mode"`
+	Raw     string `json:"raw"`
+	Options struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	} `json:"options"`
+}
+
+func (req CollectionRequest) postman() postmanItem {
+	request := &postmanRequest{
+		Method:      req.Method,
+		Description: req.Description,
+		Header:      []postmanHeader{},
+		URL:         postmanURL{Raw: "{{baseUrl}}", Host: []string{"{{baseUrl}}"}, Path: []string{}},
+	}
+	for _, segment := range req.Segments {
+		if segment.Wildcard != "" {
+			request.URL.Path = append(request.URL.Path, ":"+segment.Wildcard)
+		} else {
+			request.URL.Path = append(request.URL.Path, segment.Literal)
+		}
+	}
+	request.URL.Raw += "/" + strings.Join(request.URL.Path, "/")
+	for _, variable := range req.Variables {
+		request.URL.Variable = append(request.URL.Variable, postmanVariable{Key: variable.Name, Value: variable.Value})
+	}
+	if req.Body != "" {
+		request.Header = append(request.Header, postmanHeader{Key: "Content-Type", Value: req.ContentType})
+		request.Body = &postmanBody{Mode: "raw", Raw: req.Body}
+		request.Body.Options.Raw.Language = "text"
+		if req.ContentType == "application/json" {
+			request.Body.Options.Raw.Language = "json"
+		}
+	}
+	return postmanItem{Name: req.Name, Request: request}
+}
+
+// RenderPostman writes the postman v2.1 collection file to w.
+func (cfg *Conf) RenderPostman(w io.Writer) error {
+	if cfg.Postman == nil {
+		return fmt.Errorf("postman collection is not configured")
+	}
+	collection := buildCollection(cfg, cfg.Postman.Name, cfg.Postman.BaseURL)
+	postman := postmanCollection{
+		Info: postmanInfo{
+			Name:        collection.Name,
+			Description: fmt.Sprintf("Generated by muxc %s from %s.", collection.MuxcVersion, collection.SourceFile),
+			Schema:      postmanSchema,
+		},
+		Item:     []postmanItem{},
+		Variable: []postmanVariable{{Key: "baseUrl", Value: collection.BaseURL}},
+	}
+	for _, folder := range collection.Folders {
+		item := postmanItem{Name: folder.Name, Item: []postmanItem{}}
+		for _, request := range folder.Requests {
+			item.Item = append(item.Item, request.postman())
+		}
+		postman.Item = append(postman.Item, item)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(postman)
+}
+
+// RenderHTTPFile writes the .http file to w.
+func (cfg *Conf) RenderHTTPFile(w io.Writer) error {
+	if cfg.HTTPFile == nil {
+		return fmt.Errorf("http file is not configured")
+	}
+	return templates.ExecuteTemplate(w, "api.http.tmpl", buildCollection(cfg, "", cfg.HTTPFile.BaseURL))
+}
+
+func createPostmanFile(cfg *Conf, basedir string) error {
+	if cfg.Postman.Out == "" {
+		return fmt.Errorf("postman out directory is not configured")
+	}
+	if cfg.Postman.File == "" {
+		cfg.Postman.File = "postman_collection.json"
+	}
+	return writeFile(path.Join(basedir, cfg.Postman.Out), cfg.Postman.File, cfg.RenderPostman)
+}
+
+func createHTTPFile(cfg *Conf, basedir string) error {
+	if cfg.HTTPFile.Out == "" {
+		return fmt.Errorf("http file out directory is not configured")
+	}
+	if cfg.HTTPFile.File == "" {
+		cfg.HTTPFile.File = "api.http"
+	}
+	return writeFile(path.Join(basedir, cfg.HTTPFile.Out), cfg.HTTPFile.File, cfg.RenderHTTPFile)
+}
@@ -0,0 +1,78 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCollections(t *testing.T) {
+	cfg, err := parseTest(t, `httpFile:
+  out: docs
+postman:
+  out: docs
+routes:
+  - paths:
+      - GET /pets/{id:int64} ;handlers.ReadPet
+      - GET /users/{id:uuid} ;handlers.ReadUser
+      - path: PUT /pets ;handlers.CreatePet
+        exampleBody: {name: Rex}
+      - path: PUT /notes ;handlers.CreateNote
+        exampleBody: a note
+      - path: PUT /raw ;handlers.CreateRaw
+        exampleBody: '{"name": "Rex"}'
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	if err := cfg.RenderHTTPFile(out); err != nil {
+		t.Fatal(err)
+	}
+	// the variables of wildcards with the same name are scoped by their requests
+	for _, want := range []string{
+		"### ReadPet\n@readPet_id = 1\nGET {{baseUrl}}/pets/{{readPet_id}}\n",
+		"### ReadUser\n@readUser_id = 00000000-0000-0000-0000-000000000000\nGET {{baseUrl}}/users/{{readUser_id}}\n",
+		"### CreatePet\nPUT {{baseUrl}}/pets\nContent-Type: application/json\n",
+		"### CreateNote\nPUT {{baseUrl}}/notes\nContent-Type: text/plain\n\na note",
+		"### CreateRaw\nPUT {{baseUrl}}/raw\nContent-Type: application/json\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("http file doesn't contain %q:\n%s", want, out.String())
+		}
+	}
+	out.Reset()
+	if err := cfg.RenderPostman(out); err != nil {
+		t.Fatal(err)
+	}
+	var postman postmanCollection
+	if err := json.Unmarshal(out.Bytes(), &postman); err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]struct {
+		contentType string
+		language    string
+	}{
+		"CreatePet":  {"application/json", "json"},
+		"CreateNote": {"text/plain", "text"},
+		"CreateRaw":  {"application/json", "json"},
+	}
+	checked := 0
+	for _, item := range postman.Item[0].Item {
+		test, ok := tests[item.Name]
+		if !ok {
+			continue
+		}
+		checked++
+		if got := item.Request.Header; len(got) != 1 || got[0].Key != "Content-Type" || got[0].Value != test.contentType {
+			t.Errorf("%s: got headers %v, want content type %s", item.Name, got, test.contentType)
+		}
+		if got := item.Request.Body.Options.Raw.Language; got != test.language {
+			t.Errorf("%s: got language %s, want %s", item.Name, got, test.language)
+		}
+	}
+	if checked != len(tests) {
+		t.Errorf("got %d requests with a body, want %d", checked, len(tests))
+	}
+}
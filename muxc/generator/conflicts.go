@@ -0,0 +1,350 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+)
+
+// The pattern matching rules below are ported from net/http (pattern.go), so that
+// conflicting routes are reported at generation time instead of panicking when
+// registered in the ServeMux.
+
+type muxPattern struct {
+	str      string
+	method   string
+	host     string
+	segments []muxSegment
+}
+
+type muxSegment struct {
+	s     string
+	wild  bool
+	multi bool
+}
+
+func (p *muxPattern) lastSegment() muxSegment {
+	return p.segments[len(p.segments)-1]
+}
+
+func parseMuxPattern(s string) (*muxPattern, error) {
+	if len(s) == 0 {
+		return nil, errors.New("empty pattern")
+	}
+	method, rest, found := s, "", false
+	if i := strings.IndexAny(s, " \t"); i >= 0 {
+		method, rest, found = s[:i], strings.TrimLeft(s[i+1:], " \t"), true
+	}
+	if !found {
+		rest = method
+		method = ""
+	}
+	if method != "" && !validMethod(method) {
+		return nil, fmt.Errorf("invalid method %q", method)
+	}
+	p := &muxPattern{str: s, method: method}
+	i := strings.IndexByte(rest, '/')
+	if i < 0 {
+		return nil, errors.New("host/path missing /")
+	}
+	p.host = rest[:i]
+	rest = rest[i:]
+	if strings.IndexByte(p.host, '{') >= 0 {
+		return nil, errors.New("host contains '{' (missing initial '/'?)")
+	}
+	if method != "" && method != "CONNECT" && rest != cleanPath(rest) {
+		return nil, errors.New("non-CONNECT pattern with unclean path can never match")
+	}
+	for len(rest) > 0 {
+		rest = rest[1:]
+		if len(rest) == 0 {
+			p.segments = append(p.segments, muxSegment{wild: true, multi: true})
+			break
+		}
+		i := strings.IndexByte(rest, '/')
+		if i < 0 {
+			i = len(rest)
+		}
+		var seg string
+		seg, rest = rest[:i], rest[i:]
+		if i := strings.IndexByte(seg, '{'); i < 0 {
+			if unescaped, err := url.PathUnescape(seg); err == nil {
+				seg = unescaped
+			}
+			p.segments = append(p.segments, muxSegment{s: seg})
+			continue
+		} else if i != 0 {
+			return nil, errors.New("bad wildcard segment (must start with '{')")
+		}
+		if seg[len(seg)-1] != '}' {
+			return nil, errors.New("bad wildcard segment (must end with '}')")
+		}
+		name := seg[1 : len(seg)-1]
+		if name == "$" {
+			if len(rest) != 0 {
+				return nil, errors.New("{$} not at end")
+			}
+			p.segments = append(p.segments, muxSegment{s: "/"})
+			break
+		}
+		name, multi := strings.CutSuffix(name, "...")
+		if multi && len(rest) != 0 {
+			return nil, errors.New("{...} wildcard not at end")
+		}
+		p.segments = append(p.segments, muxSegment{s: name, wild: true, multi: multi})
+	}
+	return p, nil
+}
+
+func validMethod(method string) bool {
+	return len(method) > 0 && strings.IndexFunc(method, func(r rune) bool {
+		return r <= ' ' || r >= 0x7f || strings.ContainsRune("()<>@,;:\\\"/[]?={}", r)
+	}) == -1
+}
+
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	if p[0] != '/' {
+		p = "/" + p
+	}
+	np := path.Clean(p)
+	if p[len(p)-1] == '/' && np != "/" {
+		if len(p) == len(np)+1 && strings.HasPrefix(p, np) {
+			np = p
+		} else {
+			np += "/"
+		}
+	}
+	return np
+}
+
+type relationship string
+
+const (
+	equivalent   relationship = "equivalent"
+	moreGeneral  relationship = "moreGeneral"
+	moreSpecific relationship = "moreSpecific"
+	disjoint     relationship = "disjoint"
+	overlaps     relationship = "overlaps"
+)
+
+// conflictsWith reports whether there is a request that both patterns match but
+// neither has higher precedence than the other.
+func (p1 *muxPattern) conflictsWith(p2 *muxPattern) bool {
+	if p1.host != p2.host {
+		return false
+	}
+	rel := p1.comparePathsAndMethods(p2)
+	return rel == equivalent || rel == overlaps
+}
+
+func (p1 *muxPattern) comparePathsAndMethods(p2 *muxPattern) relationship {
+	mrel := p1.compareMethods(p2)
+	if mrel == disjoint {
+		return disjoint
+	}
+	return combineRelationships(mrel, p1.comparePaths(p2))
+}
+
+func (p1 *muxPattern) compareMethods(p2 *muxPattern) relationship {
+	if p1.method == p2.method {
+		return equivalent
+	}
+	if p1.method == "" {
+		return moreGeneral
+	}
+	if p2.method == "" {
+		return moreSpecific
+	}
+	if p1.method == "GET" && p2.method == "HEAD" {
+		return moreGeneral
+	}
+	if p2.method == "GET" && p1.method == "HEAD" {
+		return moreSpecific
+	}
+	return disjoint
+}
+
+func (p1 *muxPattern) comparePaths(p2 *muxPattern) relationship {
+	if len(p1.segments) != len(p2.segments) && !p1.lastSegment().multi && !p2.lastSegment().multi {
+		return disjoint
+	}
+	var segs1, segs2 []muxSegment
+	rel := equivalent
+	for segs1, segs2 = p1.segments, p2.segments; len(segs1) > 0 && len(segs2) > 0; segs1, segs2 = segs1[1:], segs2[1:] {
+		if rel = combineRelationships(rel, compareSegments(segs1[0], segs2[0])); rel == disjoint {
+			return rel
+		}
+	}
+	if len(segs1) == 0 && len(segs2) == 0 {
+		return rel
+	}
+	if len(segs1) < len(segs2) && p1.lastSegment().multi {
+		return combineRelationships(rel, moreGeneral)
+	}
+	if len(segs2) < len(segs1) && p2.lastSegment().multi {
+		return combineRelationships(rel, moreSpecific)
+	}
+	return disjoint
+}
+
+func compareSegments(s1, s2 muxSegment) relationship {
+	if s1.multi && s2.multi {
+		return equivalent
+	}
+	if s1.multi {
+		return moreGeneral
+	}
+	if s2.multi {
+		return moreSpecific
+	}
+	if s1.wild && s2.wild {
+		return equivalent
+	}
+	if s1.wild {
+		if s2.s == "/" {
+			return disjoint
+		}
+		return moreGeneral
+	}
+	if s2.wild {
+		if s1.s == "/" {
+			return disjoint
+		}
+		return moreSpecific
+	}
+	if s1.s == s2.s {
+		return equivalent
+	}
+	return disjoint
+}
+
+func combineRelationships(r1, r2 relationship) relationship {
+	switch r1 {
+	case equivalent:
+		return r2
+	case disjoint:
+		return disjoint
+	case overlaps:
+		if r2 == disjoint {
+			return disjoint
+		}
+		return overlaps
+	default:
+		switch r2 {
+		case equivalent:
+			return r1
+		case inverseRelationship(r1):
+			return overlaps
+		default:
+			return r2
+		}
+	}
+}
+
+func inverseRelationship(r relationship) relationship {
+	switch r {
+	case moreSpecific:
+		return moreGeneral
+	case moreGeneral:
+		return moreSpecific
+	default:
+		return r
+	}
+}
+
+// commonPath returns a path that both patterns match, assuming there is one.
+func commonPath(p1, p2 *muxPattern) string {
+	b := &strings.Builder{}
+	var segs1, segs2 []muxSegment
+	for segs1, segs2 = p1.segments, p2.segments; len(segs1) > 0 && len(segs2) > 0; segs1, segs2 = segs1[1:], segs2[1:] {
+		if s1 := segs1[0]; s1.wild {
+			writeSegment(b, segs2[0])
+		} else {
+			writeSegment(b, s1)
+		}
+	}
+	for _, s := range segs1 {
+		writeSegment(b, s)
+	}
+	for _, s := range segs2 {
+		writeSegment(b, s)
+	}
+	return b.String()
+}
+
+func writeSegment(b *strings.Builder, s muxSegment) {
+	b.WriteByte('/')
+	if !s.multi && s.s != "/" {
+		b.WriteString(s.s)
+	}
+}
+
+func describeConflict(p1, p2 *muxPattern) string {
+	mrel := p1.compareMethods(p2)
+	prel := p1.comparePaths(p2)
+	if combineRelationships(mrel, prel) == equivalent {
+		return fmt.Sprintf("%s matches the same requests as %s", p1.str, p2.str)
+	}
+	if mrel == moreGeneral && prel == moreSpecific {
+		return fmt.Sprintf("%s matches more methods than %s, but has a more specific path pattern", p1.str, p2.str)
+	}
+	if mrel == moreSpecific && prel == moreGeneral {
+		return fmt.Sprintf("%s matches fewer methods than %s, but has a more general path pattern", p1.str, p2.str)
+	}
+	return fmt.Sprintf("%s and %s both match some paths, like %q, but neither is more specific than the other", p1.str, p2.str, commonPath(p1, p2))
+}
+
+type registeredPattern struct {
+	pattern  *muxPattern
+	position Position
+	profiles []string
+}
+
+// profilesHint explains why patterns of routes restricted to different profiles conflict.
+func profilesHint(profiles1, profiles2 []string) string {
+	if len(profiles1) == 0 || len(profiles2) == 0 || slices.ContainsFunc(profiles1, func(profile string) bool { return slices.Contains(profiles2, profile) }) {
+		return ""
+	}
+	return fmt.Sprintf(", both are registered when the build tags of profiles %s and %s are enabled together", strings.Join(profiles1, ", "), strings.Join(profiles2, ", "))
+}
+
+// checkConflicts validates every pattern as the ServeMux would, returning an error naming
+// both yaml locations of conflicting patterns and warnings for ambiguous subtree patterns.
+// Routes of different profiles are checked too, as their build tags can be enabled together.
+func checkConflicts(cfg *Conf) ([]string, error) {
+	registered := []registeredPattern{}
+	for i := range cfg.Routes {
+		for _, p := range cfg.Routes[i].ParsedPaths {
+			str := p.MuxPattern
+			pattern, err := parseMuxPattern(str)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern '%s' (%s): %w", str, p.Position, err)
+			}
+			for _, other := range registered {
+				if pattern.conflictsWith(other.pattern) {
+					return nil, fmt.Errorf("pattern '%s' (%s) conflicts with pattern '%s' (%s): %s%s", str, p.Position, other.pattern.str, other.position, describeConflict(pattern, other.pattern), profilesHint(p.Profiles, other.profiles))
+				}
+			}
+			registered = append(registered, registeredPattern{pattern: pattern, position: p.Position, profiles: p.Profiles})
+		}
+	}
+	warnings := []string{}
+	for _, subtree := range registered {
+		if !subtree.pattern.lastSegment().multi || subtree.pattern.lastSegment().s != "" {
+			continue
+		}
+		for _, other := range registered {
+			if other.pattern.host == subtree.pattern.host && subtree.pattern.comparePathsAndMethods(other.pattern) == moreGeneral {
+				warnings = append(warnings, fmt.Sprintf("pattern '%s' (%s) matches every path under it, not only the ones of other routes like '%s' (%s), use '%s{$}' if only the exact path should match", subtree.pattern.str, subtree.position, other.pattern.str, other.position, subtree.pattern.str))
+				break
+			}
+		}
+	}
+	return warnings, nil
+}
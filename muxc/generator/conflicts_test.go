@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"net/http"
+	"testing"
+)
+
+// TestConflictsWith checks the ported pattern rules against cases of net/http pattern_test.go,
+// and against the ServeMux itself.
+func TestConflictsWith(t *testing.T) {
+	tests := []struct {
+		p1, p2 string
+		want   bool
+	}{
+		// method vs no method
+		{"/a", "/a", true},
+		{"GET /a", "/a", false},
+		{"/a", "GET /a", false},
+		{"GET /a", "GET /a", true},
+		{"GET /a", "HEAD /a", false},
+		{"GET /a", "POST /a", false},
+		{"GET /{x}", "/a", true},
+		{"GET /{x...}", "/a/{y}", true},
+		// host vs no host
+		{"example.com/a", "/a", false},
+		{"example.com/a", "example.com/a", true},
+		{"example.com/a", "example.org/a", false},
+		{"example.com/{x}", "/a", false},
+		// {$}
+		{"/{$}", "/", false},
+		{"/a/{$}", "/a/", false},
+		{"/a/{$}", "/a/{x...}", false},
+		{"/a/{$}", "/a/{$}", true},
+		{"/{x}/{$}", "/a/{$}", false},
+		{"/{x}/{$}", "/a/{y}", false},
+		// wildcard vs literal
+		{"/{x}", "/a", false},
+		{"/a/{x}", "/{y}/b", true},
+		{"/a/{x}", "/a/{y...}", false},
+		{"/{x}/b/{y...}", "/a/{z}/c", true},
+		{"/a/b/{x}", "/a/{y}/{z}", false},
+		// equivalent patterns
+		{"/{x}", "/{y}", true},
+		{"/a/", "/a/{x...}", true},
+		{"/a/%62", "/a/b", true},
+		{"GET /{x}/{y...}", "GET /{z}/{w...}", true},
+	}
+	for _, test := range tests {
+		p1, err := parseMuxPattern(test.p1)
+		if err != nil {
+			t.Fatalf("%s: %v", test.p1, err)
+		}
+		p2, err := parseMuxPattern(test.p2)
+		if err != nil {
+			t.Fatalf("%s: %v", test.p2, err)
+		}
+		if got := p1.conflictsWith(p2); got != test.want {
+			t.Errorf("%q conflicts with %q: got %t, want %t", test.p1, test.p2, got, test.want)
+		}
+		if got := p2.conflictsWith(p1); got != test.want {
+			t.Errorf("%q conflicts with %q: got %t, want %t", test.p2, test.p1, got, test.want)
+		}
+		mux := http.NewServeMux()
+		mux.Handle(test.p1, http.NotFoundHandler())
+		if conflict := registerPattern(mux, test.p2) != nil; conflict != test.want {
+			t.Errorf("ServeMux registering %q after %q: conflict %t, want %t", test.p2, test.p1, conflict, test.want)
+		}
+	}
+}
+
+func TestParseMuxPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		valid   bool
+	}{
+		{"/", true},
+		{"GET /a/{x}/{y...}", true},
+		{"example.com/a/{$}", true},
+		{"", false},
+		{"GET", false},
+		{"a", false},
+		{"BAD@ /a", false},
+		{"GET /a/../b", false},
+		{"CONNECT /a/../b", true},
+		{"/a/{$}/b", false},
+		{"/a/{x...}/b", false},
+		{"/a/x{y}", false},
+		{"/a/{x", false},
+		{"{x}/a", false},
+	}
+	for _, test := range tests {
+		_, err := parseMuxPattern(test.pattern)
+		if valid := err == nil; valid != test.valid {
+			t.Errorf("%q: valid %t, want %t (%v)", test.pattern, valid, test.valid, err)
+		}
+		if valid := registerPattern(http.NewServeMux(), test.pattern) == nil; valid != test.valid {
+			t.Errorf("ServeMux registering %q: valid %t, want %t", test.pattern, valid, test.valid)
+		}
+	}
+}
+
+func TestCheckConflictsProfiles(t *testing.T) {
+	const conflict = "pattern 'GET /debug' (muxc.yaml:9) conflicts with pattern 'GET /debug' (muxc.yaml:7): GET /debug matches the same requests as GET /debug"
+	tests := []struct {
+		routes string
+		want   string
+	}{
+		{
+			routes: "  - paths:\n      - path: GET /debug ;handlers.A\n        profiles: [dev]\n      - path: GET /debug ;handlers.B\n        profiles: [dev, prod]\n",
+			want:   conflict,
+		},
+		{
+			routes: "  - paths:\n      - path: GET /debug ;handlers.A\n        profiles: [dev]\n      - GET /debug ;handlers.B\n",
+			want:   conflict,
+		},
+		// build tags can be enabled together, so routes of different profiles conflict too
+		{
+			routes: "  - paths:\n      - path: GET /debug ;handlers.A\n        profiles: [dev]\n      - path: GET /debug ;handlers.B\n        profiles: [prod, staging]\n",
+			want:   conflict + ", both are registered when the build tags of profiles prod, staging and dev are enabled together",
+		},
+	}
+	for _, test := range tests {
+		if _, err := parseTest(t, "routes:\n"+test.routes); err == nil || err.Error() != test.want {
+			t.Errorf("got error %v, want %q", err, test.want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package generator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+)
+
+// RouteChange is a difference between the routes of two configurations. Removed routes and changes
+// that make requests accepted by the old route fail (e.g. a different method or pattern) are breaking.
+type RouteChange struct {
+	Kind     ChangeKind
+	Breaking bool
+	Old      *ParsedPath
+	New      *ParsedPath
+	Changes  []string
+}
+
+func (c RouteChange) String() string {
+	s := string(c.Kind)
+	if c.Breaking {
+		s = "breaking: " + s
+	}
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("%s %s (%s)", s, c.New.MuxPattern, c.New.Position)
+	case ChangeRemoved:
+		return fmt.Sprintf("%s %s (%s)", s, c.Old.MuxPattern, c.Old.Position)
+	}
+	return fmt.Sprintf("%s %s (%s): %s", s, c.Old.MuxPattern, c.New.Position, strings.Join(c.Changes, ", "))
+}
+
+// routeKey identifies the endpoint of a route by its method and pattern, regardless of the wildcard names.
+func routeKey(path *ParsedPath) string {
+	key := &strings.Builder{}
+	key.WriteString(path.Method + " ")
+	pattern := path.FullPattern
+	for {
+		start := strings.Index(pattern, "{")
+		if start == -1 {
+			break
+		}
+		end := strings.Index(pattern[start:], "}") + start
+		key.WriteString(pattern[:start])
+		switch name := pattern[start+1 : end]; {
+		case name == "$":
+			key.WriteString("{$}")
+		case strings.HasSuffix(name, "..."):
+			key.WriteString("{...}")
+		default:
+			key.WriteString("{}")
+		}
+		pattern = pattern[end+1:]
+	}
+	key.WriteString(pattern)
+	return key.String()
+}
+
+func allPaths(cfg *Conf) []*ParsedPath {
+	paths := []*ParsedPath{}
+	for i := range cfg.Routes {
+		for j := range cfg.Routes[i].ParsedPaths {
+			paths = append(paths, &cfg.Routes[i].ParsedPaths[j])
+		}
+	}
+	return paths
+}
+
+// Diff compares the routes of two configurations. Routes are matched by method and pattern, and the
+// remaining ones by route name, so that a route with a new pattern is reported as changed instead
+// of removed and added.
+func Diff(old *Conf, new *Conf) []RouteChange {
+	oldPaths, newPaths := allPaths(old), allPaths(new)
+	matches := make([]*ParsedPath, len(oldPaths))
+	matched := map[*ParsedPath]bool{}
+	for i, oldPath := range oldPaths {
+		for _, newPath := range newPaths {
+			if !matched[newPath] && routeKey(oldPath) == routeKey(newPath) {
+				matches[i], matched[newPath] = newPath, true
+				break
+			}
+		}
+	}
+	for i, oldPath := range oldPaths {
+		for _, newPath := range newPaths {
+			if matches[i] == nil && !matched[newPath] && oldPath.RouteName() != "" && oldPath.RouteName() == newPath.RouteName() {
+				matches[i], matched[newPath] = newPath, true
+				break
+			}
+		}
+	}
+	changes := []RouteChange{}
+	for i, oldPath := range oldPaths {
+		if matches[i] == nil {
+			changes = append(changes, RouteChange{Kind: ChangeRemoved, Breaking: true, Old: oldPath})
+			continue
+		}
+		if change := diffPath(oldPath, matches[i]); len(change.Changes) > 0 {
+			changes = append(changes, change)
+		}
+	}
+	for _, newPath := range newPaths {
+		if !matched[newPath] {
+			changes = append(changes, RouteChange{Kind: ChangeAdded, New: newPath})
+		}
+	}
+	return changes
+}
+
+func diffPath(old *ParsedPath, new *ParsedPath) RouteChange {
+	change := RouteChange{Kind: ChangeChanged, Old: old, New: new, Changes: []string{}}
+	if old.Method != new.Method {
+		change.Changes = append(change.Changes, fmt.Sprintf("method '%s' -> '%s'", old.Method, new.Method))
+		change.Breaking = change.Breaking || new.Method != ""
+	}
+	if old.FullPattern != new.FullPattern {
+		change.Changes = append(change.Changes, fmt.Sprintf("pattern '%s' -> '%s'", old.FullPattern, new.FullPattern))
+		change.Breaking = change.Breaking || routeKey(&ParsedPath{FullPattern: old.FullPattern}) != routeKey(&ParsedPath{FullPattern: new.FullPattern})
+	}
+	for i := range min(len(old.Wildcards), len(new.Wildcards)) {
+		if oldWildcard, newWildcard := old.Wildcards[i], new.Wildcards[i]; oldWildcard.Type != newWildcard.Type || oldWildcard.Regexp != newWildcard.Regexp {
+			change.Changes = append(change.Changes, fmt.Sprintf("wildcard '%s' type '%s' -> '%s'", newWildcard.Name, wildcardTypeName(oldWildcard), wildcardTypeName(newWildcard)))
+			change.Breaking = change.Breaking || newWildcard.Typed()
+		}
+	}
+	if old.RouteName() != new.RouteName() {
+		change.Changes = append(change.Changes, fmt.Sprintf("name '%s' -> '%s'", old.RouteName(), new.RouteName()))
+	}
+	if old.Handler != new.Handler {
+		change.Changes = append(change.Changes, fmt.Sprintf("handler '%s' -> '%s'", old.Handler, new.Handler))
+	}
+	if !slices.Equal(old.Stack, new.Stack) {
+		change.Changes = append(change.Changes, fmt.Sprintf("middlewares [%s] -> [%s]", strings.Join(old.Stack, ", "), strings.Join(new.Stack, ", ")))
+	}
+	if !slices.Equal(old.Profiles, new.Profiles) {
+		change.Changes = append(change.Changes, fmt.Sprintf("profiles [%s] -> [%s]", strings.Join(old.Profiles, ", "), strings.Join(new.Profiles, ", ")))
+		// the route is no longer registered in binaries built with a profile it was registered with
+		for _, profile := range old.Profiles {
+			change.Breaking = change.Breaking || len(new.Profiles) > 0 && !slices.Contains(new.Profiles, profile)
+		}
+		change.Breaking = change.Breaking || len(old.Profiles) == 0
+	}
+	return change
+}
+
+func wildcardTypeName(wc Wildcard) string {
+	if wc.Type == WildcardRegexp {
+		return wc.Regexp
+	}
+	return wc.Type
+}
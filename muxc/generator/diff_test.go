@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want []string
+	}{
+		{
+			name: "wildcard renamed",
+			old:  "      - GET /pets/{id} ;handlers.Read\n",
+			new:  "      - GET /pets/{petID} ;handlers.Read\n",
+			want: []string{"changed GET /pets/{id} (muxc.yaml:7): pattern '/pets/{id}' -> '/pets/{petID}'"},
+		},
+		{
+			name: "added and removed",
+			old:  "      - GET /pets ;handlers.List\n",
+			new:  "      - GET /animals ;handlers.ListAnimals\n",
+			want: []string{"breaking: removed GET /pets (muxc.yaml:7)", "added GET /animals (muxc.yaml:7)"},
+		},
+		{
+			name: "renamed pattern matched by name",
+			old:  "      - GET /pets/{id} ;handlers.Read\n",
+			new:  "      - GET /animals/{id} ;handlers.Read\n",
+			want: []string{"breaking: changed GET /pets/{id} (muxc.yaml:7): pattern '/pets/{id}' -> '/animals/{id}'"},
+		},
+		{
+			name: "method",
+			old:  "      - GET /pets ;handlers.List\n",
+			new:  "      - /pets ;handlers.List\n",
+			want: []string{"changed GET /pets (muxc.yaml:7): method 'GET' -> ''"},
+		},
+		{
+			name: "stricter and looser wildcard types",
+			old:  "      - GET /pets/{id} ;handlers.Read\n      - GET /users/{id:int} ;handlers.User\n",
+			new:  "      - GET /pets/{id:int64} ;handlers.Read\n      - GET /users/{id} ;handlers.User\n",
+			want: []string{
+				"breaking: changed GET /pets/{id} (muxc.yaml:7): wildcard 'id' type '' -> 'int64'",
+				"changed GET /users/{id} (muxc.yaml:8): wildcard 'id' type 'int' -> ''",
+			},
+		},
+		{
+			name: "handler and middlewares",
+			old:  "      - GET /pets ;handlers.List ;handlers.Auth\n",
+			new:  "      - path: GET /pets ;handlers.ListV2\n        name: List\n",
+			want: []string{"changed GET /pets (muxc.yaml:7): handler 'handlers.List' -> 'handlers.ListV2', middlewares [handlers.Auth] -> []"},
+		},
+		{
+			name: "restricted to a profile",
+			old:  "      - GET /debug ;handlers.Debug\n",
+			new:  "      - path: GET /debug ;handlers.Debug\n        profiles: [dev]\n",
+			want: []string{"breaking: changed GET /debug (muxc.yaml:7): profiles [] -> [dev]"},
+		},
+		{
+			name: "profile added",
+			old:  "      - path: GET /debug ;handlers.Debug\n        profiles: [dev]\n",
+			new:  "      - path: GET /debug ;handlers.Debug\n        profiles: [dev, staging]\n",
+			want: []string{"changed GET /debug (muxc.yaml:7): profiles [dev] -> [dev, staging]"},
+		},
+		{
+			name: "profile restriction removed",
+			old:  "      - path: GET /debug ;handlers.Debug\n        profiles: [dev]\n",
+			new:  "      - GET /debug ;handlers.Debug\n",
+			want: []string{"changed GET /debug (muxc.yaml:7): profiles [dev] -> []"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			old, err := parseTest(t, "routes:\n  - paths:\n"+test.old)
+			if err != nil {
+				t.Fatal(err)
+			}
+			new, err := parseTest(t, "routes:\n  - paths:\n"+test.new)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, change := range Diff(old, new) {
+				got = append(got, change.String())
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("got changes %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"path"
+)
+
+type DocsConf struct {
+	Out   string `yaml:"out"`
+	File  string `yaml:"file"`
+	Title string `yaml:"title"`
+}
+
+// DocsFile is the model of the markdown API reference.
+type DocsFile struct {
+	Title       string
+	MuxcVersion string
+	SourceFile  string
+	Routes      []Routes
+}
+
+// RenderDocs writes the markdown API reference to w.
+func (cfg *Conf) RenderDocs(w io.Writer) error {
+	if cfg.Docs == nil {
+		return fmt.Errorf("docs are not configured")
+	}
+	file := DocsFile{
+		Title:       cfg.Docs.Title,
+		MuxcVersion: cfg.MuxcVersion,
+		SourceFile:  cfg.SourceFile,
+		Routes:      cfg.Routes,
+	}
+	if file.Title == "" {
+		file.Title = cfg.Package + " API"
+	}
+	return templates.ExecuteTemplate(w, "API.md.tmpl", file)
+}
+
+func createDocsFile(cfg *Conf, basedir string) error {
+	if cfg.Docs.Out == "" {
+		return fmt.Errorf("docs out directory is not configured")
+	}
+	if cfg.Docs.File == "" {
+		cfg.Docs.File = "API.md"
+	}
+	return writeFile(path.Join(basedir, cfg.Docs.Out), cfg.Docs.File, cfg.RenderDocs)
+}
@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRenderDocs(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   []string
+	}{
+		{
+			name:   "default title",
+			config: "docs:\n  out: docs\nroutes:\n  - paths:\n      - GET /pets ;handlers.ListPets\n",
+			want:   []string{"# routes API\n\n## `/`\n\n### ListPets\n\n`GET /pets`\n\n- Handler: `handlers.ListPets`\n- Source: muxc.yaml:9"},
+		},
+		{
+			name: "descriptions",
+			config: `docs:
+  out: docs
+  title: Pet store
+routes:
+  - base: /api
+    description: Pet store API.
+    use:
+      - logger
+    tags: [pets]
+    paths:
+      - path: GET /pets/{id:int64} ;handlers.ReadPet ;cache
+        description: |
+          Returns the pet with the given id.
+        out: Pet
+`,
+			want: []string{
+				"# Pet store\n\n## `/api`\n\nPet store API.\n\n- Middlewares: `logger`\n- Tags: pets\n",
+				"### ReadPet\n\n`GET /api/pets/{id}`\n\nReturns the pet with the given id.\n\n- Wildcards:\n  - `id`: int64\n",
+				"- Middlewares (outermost first): `logger`, `cache`\n- Response body: `Pet`\n- Tags: pets\n",
+			},
+		},
+		{
+			name:   "any method and regexp wildcard",
+			config: "docs:\n  out: docs\nroutes:\n  - paths:\n      - /files/{name:[a-z]+} ;handlers.File\n      - /raw/{path...} ;handlers.Raw\n",
+			want:   []string{"`ANY /files/{name}`\n\n- Wildcards:\n  - `name`: matches `[a-z]+`\n", "`ANY /raw/{path...}`\n\n- Wildcards:\n  - `path...`\n"},
+		},
+		{
+			name:   "deprecated",
+			config: "docs:\n  out: docs\nroutes:\n  - paths:\n      - path: GET /pets ;handlers.ListPets\n        deprecated: true\n",
+			want:   []string{"`GET /pets`\n\n> **Deprecated**\n\n- Handler"},
+		},
+		{
+			name:   "deprecation message",
+			config: "docs:\n  out: docs\nroutes:\n  - paths:\n      - path: GET /pets ;handlers.ListPets\n        deprecated: use SearchPets\n",
+			want:   []string{"`GET /pets`\n\n> **Deprecated**: use SearchPets\n\n- Handler"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg, err := parseTest(t, test.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out := &bytes.Buffer{}
+			if err := cfg.RenderDocs(out); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range test.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("docs don't contain %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRenderDocsNotConfigured(t *testing.T) {
+	cfg, err := parseTest(t, "routes:\n  - paths:\n      - GET /pets ;handlers.ListPets\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.RenderDocs(&bytes.Buffer{}); err == nil || err.Error() != "docs are not configured" {
+		t.Errorf("got error %v", err)
+	}
+}
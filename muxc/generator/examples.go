@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"fmt"
+	"mime"
+	"net/textproto"
+	"path"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Example is a response of a route served by mock servers, the body is either defined inline
+// (mappings and sequences are encoded as JSON) or read from a file relative to the yaml file.
+type Example struct {
+	Name     string            `yaml:"name"`
+	Status   int               `yaml:"status"`
+	Headers  map[string]string `yaml:"headers"`
+	Body     yaml.Node         `yaml:"body"`
+	BodyFile string            `yaml:"bodyFile"`
+}
+
+type Header struct {
+	Name  string
+	Value string
+}
+
+// ExampleResponse is a parsed example, with its body read and its headers sorted by name.
+type ExampleResponse struct {
+	Name    string
+	Status  int
+	Headers []Header
+	Body    string
+}
+
+func parseExamples(examples []Example, readFile func(string) ([]byte, error)) ([]ExampleResponse, error) {
+	responses := []ExampleResponse{}
+	for i, example := range examples {
+		response := ExampleResponse{Name: example.Name, Status: example.Status, Headers: []Header{}}
+		if response.Name == "" {
+			response.Name = fmt.Sprint(i + 1)
+		}
+		for _, other := range responses {
+			if other.Name == response.Name {
+				return nil, fmt.Errorf("example '%s' is duplicated", response.Name)
+			}
+		}
+		if response.Status == 0 {
+			response.Status = 200
+		}
+		if response.Status < 100 || response.Status > 599 {
+			return nil, fmt.Errorf("example '%s' has an invalid status %d", response.Name, response.Status)
+		}
+		contentType := ""
+		switch {
+		case example.BodyFile != "" && example.Body.Kind != 0:
+			return nil, fmt.Errorf("example '%s' has both body and bodyFile", response.Name)
+		case example.BodyFile != "":
+			data, err := readFile(example.BodyFile)
+			if err != nil {
+				return nil, fmt.Errorf("error reading body file of example '%s': %w", response.Name, err)
+			}
+			response.Body = string(data)
+			contentType = mime.TypeByExtension(path.Ext(example.BodyFile))
+		case example.Body.Kind == yaml.MappingNode || example.Body.Kind == yaml.SequenceNode:
+			contentType = "application/json"
+			fallthrough
+		default:
+			body, err := exampleBody(&example.Body)
+			if err != nil {
+				return nil, fmt.Errorf("invalid body of example '%s': %w", response.Name, err)
+			}
+			response.Body = body
+		}
+		for name, value := range example.Headers {
+			response.Headers = append(response.Headers, Header{Name: textproto.CanonicalMIMEHeaderKey(name), Value: value})
+		}
+		if contentType != "" && !slices.ContainsFunc(response.Headers, func(h Header) bool { return h.Name == "Content-Type" }) {
+			response.Headers = append(response.Headers, Header{Name: "Content-Type", Value: contentType})
+		}
+		slices.SortFunc(response.Headers, func(a, b Header) int { return strings.Compare(a.Name, b.Name) })
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
@@ -0,0 +1,254 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+type Import struct {
+	Name string
+	Path string
+}
+
+// String returns the import spec as written in a go import declaration.
+func (imp Import) String() string {
+	if imp.Name == "" {
+		return strconv.Quote(imp.Path)
+	}
+	return imp.Name + " " + strconv.Quote(imp.Path)
+}
+
+// parseImport parses an entry of the imports list, either an import path or an aliased
+// import like mw "github.com/enolgor/muxc/examples/basic/middlewares".
+func parseImport(spec string) (Import, error) {
+	spec = strings.TrimSpace(spec)
+	name, quoted, aliased := strings.Cut(spec, " ")
+	if !aliased {
+		return Import{Path: strings.Trim(spec, `"`)}, nil
+	}
+	importPath, err := strconv.Unquote(strings.TrimSpace(quoted))
+	if err != nil {
+		importPath = strings.TrimSpace(quoted)
+	}
+	if !isIdentifier(name) || importPath == "" || strings.ContainsAny(importPath, " \"") {
+		return Import{}, fmt.Errorf("invalid import '%s'", spec)
+	}
+	return Import{Name: name, Path: importPath}, nil
+}
+
+// packageIndex maps package names to the import paths of the standard library and
+// enclosing module packages with that name.
+type packageIndex struct {
+	names    map[string][]string
+	explicit map[string]string
+}
+
+func moduleRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func isInternal(importPath string) bool {
+	for _, elem := range strings.Split(importPath, "/") {
+		if elem == "internal" || elem == "vendor" {
+			return true
+		}
+	}
+	return false
+}
+
+// loadPackages lists the standard library, the packages of the module enclosing basedir
+// (except the one of the generated routes) and the explicit imports, to know their names.
+func loadPackages(cfg *Conf, basedir string, explicit []Import) (*packageIndex, error) {
+	dir, err := filepath.Abs(basedir)
+	if err != nil {
+		return nil, err
+	}
+	outDir := filepath.Join(dir, cfg.Out)
+	patterns := []string{"std"}
+	if root := moduleRoot(dir); root != "" {
+		dir = root
+		patterns = append(patterns, "./...")
+	}
+	for _, imp := range explicit {
+		patterns = append(patterns, imp.Path)
+	}
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName | packages.NeedFiles, Dir: dir}, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	index := &packageIndex{names: map[string][]string{}, explicit: map[string]string{}}
+	for _, pkg := range pkgs {
+		if pkg.Name == "" {
+			continue
+		}
+		if slices.ContainsFunc(explicit, func(imp Import) bool { return imp.Path == pkg.PkgPath }) {
+			index.explicit[pkg.PkgPath] = pkg.Name
+		}
+		if pkg.Name == "main" || isInternal(pkg.PkgPath) || pkg.Dir == outDir || slices.Contains(index.names[pkg.Name], pkg.PkgPath) {
+			continue
+		}
+		index.names[pkg.Name] = append(index.names[pkg.Name], pkg.PkgPath)
+	}
+	return index, nil
+}
+
+// resolveImports returns the imports needed by the given package qualifiers (mapped to the
+// expression referencing them), using the explicit imports first and inferring the rest from
+// the standard library and the enclosing module. Imports already present are omitted.
+// A qualifier neither imported nor inferred is an error, as the generated code wouldn't compile.
+func resolveImports(cfg *Conf, basedir string, refs map[string]string, present []string) ([]string, error) {
+	explicit := make([]Import, len(cfg.Imports))
+	for i := range cfg.Imports {
+		imp, err := parseImport(cfg.Imports[i])
+		if err != nil {
+			return nil, err
+		}
+		explicit[i] = imp
+	}
+	qualifiers := make([]string, 0, len(refs))
+	for qualifier := range refs {
+		qualifiers = append(qualifiers, qualifier)
+	}
+	sort.Strings(qualifiers)
+	name := func(imp Import) string {
+		if imp.Name != "" {
+			return imp.Name
+		}
+		if cfg.packages != nil && cfg.packages.explicit[imp.Path] != "" {
+			return cfg.packages.explicit[imp.Path]
+		}
+		return importName(imp.Path)
+	}
+	resolved := map[string]Import{}
+	unresolved := func() []string {
+		missing := []string{}
+		for _, qualifier := range qualifiers {
+			if _, ok := resolved[qualifier]; ok {
+				continue
+			}
+			for _, imp := range explicit {
+				if name(imp) == qualifier {
+					resolved[qualifier] = imp
+					break
+				}
+			}
+			if _, ok := resolved[qualifier]; !ok {
+				missing = append(missing, qualifier)
+			}
+		}
+		return missing
+	}
+	if missing := unresolved(); len(missing) > 0 {
+		if cfg.packages == nil {
+			index, err := loadPackages(cfg, basedir, explicit)
+			if err != nil {
+				return nil, fmt.Errorf("unable to list packages to infer imports, %s should be added to imports: %w", strings.Join(missing, ", "), err)
+			}
+			cfg.packages = index
+		}
+		for _, qualifier := range unresolved() {
+			candidates := cfg.packages.names[qualifier]
+			switch len(candidates) {
+			case 0:
+				return nil, fmt.Errorf("package '%s' referenced by '%s' was not found, it should be added to imports", qualifier, refs[qualifier])
+			case 1:
+				resolved[qualifier] = Import{Path: candidates[0]}
+			default:
+				return nil, fmt.Errorf("package '%s' referenced by '%s' is ambiguous (%s), add the one to use to imports", qualifier, refs[qualifier], strings.Join(candidates, ", "))
+			}
+		}
+	}
+	imports := []string{}
+	for _, imp := range append(explicit, inferred(resolved, explicit)...) {
+		if !slices.ContainsFunc(qualifiers, func(qualifier string) bool { return resolved[qualifier] == imp }) {
+			continue
+		}
+		if imp.Name == "" && slices.Contains(present, imp.Path) || slices.Contains(imports, imp.String()) {
+			continue
+		}
+		imports = append(imports, imp.String())
+	}
+	return imports, nil
+}
+
+// splitStdImports splits import specs between the standard library ones, imported in the first
+// group, and the other ones.
+func splitStdImports(specs []string) ([]string, []string) {
+	std, other := []string{}, []string{}
+	for _, spec := range specs {
+		imp, err := parseImport(spec)
+		if elem, _, _ := strings.Cut(imp.Path, "/"); err == nil && !strings.Contains(elem, ".") {
+			std = append(std, spec)
+		} else {
+			other = append(other, spec)
+		}
+	}
+	return std, other
+}
+
+func inferred(resolved map[string]Import, explicit []Import) []Import {
+	imports := []Import{}
+	for _, imp := range resolved {
+		if !slices.Contains(explicit, imp) {
+			imports = append(imports, imp)
+		}
+	}
+	sort.Slice(imports, func(i, j int) bool { return imports[i].Path < imports[j].Path })
+	return imports
+}
+
+// routeQualifiers returns the package qualifiers referenced by the given args types, vars and
+// registered routes, mapped to the first expression referencing each of them.
+// A var can reference a package with its own name (logger := logger.New(...)), as it is
+// only in scope after its declaration.
+func routeQualifiers(cfg *Conf, types []string, vars Decls, registrations []Registration) (map[string]string, error) {
+	refs := map[string]string{}
+	add := func(expr string, locals []string) error {
+		found, err := qualifiers(expr)
+		if err != nil {
+			return err
+		}
+		for _, qualifier := range found {
+			if _, exists := refs[qualifier]; !exists && !slices.Contains(locals, qualifier) {
+				refs[qualifier] = expr
+			}
+		}
+		return nil
+	}
+	for _, expr := range types {
+		if err := add(expr, nil); err != nil {
+			return nil, err
+		}
+	}
+	locals := cfg.Args.Names()
+	for _, decl := range vars {
+		if err := add(decl.Expr, locals); err != nil {
+			return nil, err
+		}
+		locals = append(locals, decl.Name)
+	}
+	for _, registration := range registrations {
+		for _, expr := range append(registration.Path.handlerRefs(), registration.Path.Stack...) {
+			if err := add(expr, locals); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return refs, nil
+}
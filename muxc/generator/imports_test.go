@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestResolveImports(t *testing.T) {
+	index := &packageIndex{
+		names: map[string][]string{
+			"handlers": {"example.com/api/handlers"},
+			"template": {"html/template", "text/template"},
+		},
+		explicit: map[string]string{"example.com/mw/v2": "middlewares"},
+	}
+	tests := []struct {
+		name    string
+		imports []string
+		refs    map[string]string
+		index   *packageIndex
+		want    []string
+		err     string
+	}{
+		{
+			name:    "explicit imports without listing packages",
+			imports: []string{"github.com/go-chi/chi/v5", `mw "example.com/mw"`},
+			refs:    map[string]string{"chi": "chi.NewRouter()", "mw": "mw.Logger"},
+			want:    []string{`"github.com/go-chi/chi/v5"`, `mw "example.com/mw"`},
+		},
+		{
+			name:    "unused explicit imports",
+			imports: []string{"github.com/go-chi/chi/v5"},
+			refs:    map[string]string{},
+			want:    []string{},
+		},
+		{
+			name:    "listing packages fails",
+			imports: []string{"github.com/go-chi/chi/v5"},
+			refs:    map[string]string{"chi": "chi.NewRouter()", "handlers": "handlers.ListPets"},
+			err:     "unable to list packages to infer imports, handlers should be added to imports",
+		},
+		{
+			name:  "inferred",
+			refs:  map[string]string{"handlers": "handlers.ListPets"},
+			index: index,
+			want:  []string{`"example.com/api/handlers"`},
+		},
+		{
+			name:    "explicit package name",
+			imports: []string{"example.com/mw/v2"},
+			refs:    map[string]string{"middlewares": "middlewares.Logger"},
+			index:   index,
+			want:    []string{`"example.com/mw/v2"`},
+		},
+		{
+			name:  "not found",
+			refs:  map[string]string{"missing": "missing.Handler"},
+			index: index,
+			err:   "package 'missing' referenced by 'missing.Handler' was not found, it should be added to imports",
+		},
+		{
+			name:  "ambiguous",
+			refs:  map[string]string{"template": "template.New"},
+			index: index,
+			err:   "package 'template' referenced by 'template.New' is ambiguous (html/template, text/template)",
+		},
+	}
+	// without the go command listing packages fails, so only the explicit imports are available
+	t.Setenv("PATH", "")
+	t.Setenv("GOPACKAGESDRIVER", "off")
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &Conf{Imports: test.imports, packages: test.index}
+			got, err := resolveImports(cfg, t.TempDir(), test.refs, nil)
+			switch {
+			case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+				t.Errorf("got error %v, want %q", err, test.err)
+			case test.err == "" && err != nil:
+				t.Error(err)
+			case test.err == "" && !slices.Equal(got, test.want):
+				t.Errorf("got imports %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package generator
+
+// IRVersion is the version of the intermediate representation, incremented on incompatible changes.
+const IRVersion int = 1
+
+// IR is the JSON intermediate representation of a resolved configuration, passed to plugins.
+type IR struct {
+	Version     int        `json:"version"`
+	MuxcVersion string     `json:"muxcVersion"`
+	SourceFile  string     `json:"sourceFile"`
+	Package     string     `json:"package"`
+	Out         string     `json:"out"`
+	Imports     []IRImport `json:"imports"`
+	ArgsStyle   string     `json:"argsStyle"`
+	Router      string     `json:"router"`
+	Args        []IRDecl   `json:"args"`
+	Vars        []IRDecl   `json:"vars"`
+	Routes      []IRRoute  `json:"routes"`
+	Profiles    []string   `json:"profiles"`
+}
+
+type IRImport struct {
+	Name string `json:"name,omitempty"`
+	Path string `json:"path"`
+}
+
+// IRDecl is an arg (with its type) or a var (with its expression), vars are sorted in
+// declaration order.
+type IRDecl struct {
+	Name string `json:"name"`
+	Expr string `json:"expr"`
+}
+
+type IRRoute struct {
+	Name        string       `json:"name"`
+	Method      string       `json:"method,omitempty"`
+	Pattern     string       `json:"pattern"`
+	Path        string       `json:"path"`
+	Base        string       `json:"base"`
+	Handler     string       `json:"handler"`
+	Middlewares []string     `json:"middlewares"`
+	Wildcards   []IRWildcard `json:"wildcards"`
+	In          string       `json:"in,omitempty"`
+	Out         string       `json:"out,omitempty"`
+	Tags        []string     `json:"tags"`
+	Profiles    []string     `json:"profiles"`
+	ExampleBody string       `json:"exampleBody,omitempty"`
+	Examples    []IRExample  `json:"examples"`
+	Description string       `json:"description,omitempty"`
+	Deprecated  bool         `json:"deprecated,omitempty"`
+	Deprecation string       `json:"deprecation,omitempty"`
+	Position    IRPosition   `json:"position"`
+}
+
+type IRExample struct {
+	Name    string            `json:"name"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+}
+
+type IRWildcard struct {
+	Name      string `json:"name"`
+	Type      string `json:"type,omitempty"`
+	Regexp    string `json:"regexp,omitempty"`
+	Remainder bool   `json:"remainder,omitempty"`
+}
+
+type IRPosition struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// IR returns the intermediate representation of the configuration. Patterns are the ones
+// registered in the mux, paths keep the wildcard names without type annotations and the
+// middlewares are sorted in effective order, from the outermost to the innermost.
+func (cfg *Conf) IR() IR {
+	ir := IR{
+		Version:     IRVersion,
+		MuxcVersion: cfg.MuxcVersion,
+		SourceFile:  cfg.SourceFile,
+		Package:     cfg.Package,
+		Out:         cfg.Out,
+		Imports:     []IRImport{},
+		ArgsStyle:   cfg.ArgsStyle,
+		Router:      cfg.Router,
+		Args:        []IRDecl{},
+		Vars:        []IRDecl{},
+		Routes:      []IRRoute{},
+		Profiles:    nonNil(cfg.Profiles),
+	}
+	for _, specs := range [][]string{cfg.routesFile.StdImports, cfg.routesFile.UsedImports} {
+		for _, spec := range specs {
+			if imp, err := parseImport(spec); err == nil {
+				ir.Imports = append(ir.Imports, IRImport{Name: imp.Name, Path: imp.Path})
+			}
+		}
+	}
+	for _, decl := range cfg.Args {
+		ir.Args = append(ir.Args, IRDecl{Name: decl.Name, Expr: decl.Expr})
+	}
+	for _, decl := range cfg.OrderedVars {
+		ir.Vars = append(ir.Vars, IRDecl{Name: decl.Name, Expr: decl.Expr})
+	}
+	for i := range cfg.Routes {
+		for _, path := range cfg.Routes[i].ParsedPaths {
+			route := IRRoute{
+				Name:        path.RouteName(),
+				Method:      path.Method,
+				Pattern:     path.MuxPattern,
+				Path:        path.FullPattern,
+				Base:        cfg.Routes[i].Base,
+				Handler:     path.Handler,
+				Middlewares: nonNil(path.Stack),
+				Wildcards:   []IRWildcard{},
+				In:          path.In,
+				Out:         path.Out,
+				Tags:        nonNil(path.Tags),
+				Profiles:    nonNil(path.Profiles),
+				ExampleBody: path.ExampleBody,
+				Examples:    []IRExample{},
+				Description: path.Description,
+				Deprecated:  path.Deprecated,
+				Deprecation: path.Deprecation,
+				Position:    IRPosition{File: path.Position.File, Line: path.Position.Line},
+			}
+			for _, wc := range path.Wildcards {
+				route.Wildcards = append(route.Wildcards, IRWildcard{Name: wc.Name, Type: wc.Type, Regexp: wc.Regexp, Remainder: wc.Remainder})
+			}
+			for _, example := range path.Examples {
+				headers := map[string]string{}
+				for _, header := range example.Headers {
+					headers[header.Name] = header.Value
+				}
+				route.Examples = append(route.Examples, IRExample{Name: example.Name, Status: example.Status, Headers: headers, Body: example.Body})
+			}
+			ir.Routes = append(ir.Routes, route)
+		}
+	}
+	return ir
+}
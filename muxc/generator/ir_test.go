@@ -0,0 +1,78 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIR(t *testing.T) {
+	cfg, err := parseTest(t, `args:
+  prefix: string
+vars:
+  b: handlers.Wrap(a)
+  a: handlers.Header("x")
+routes:
+  - use: [b]
+    base: /api
+    paths:
+      - GET /pets/{id:int64} ;handlers.ReadPet(prefix) ;a
+      - path: POST /pets/{name:[a-z]+}/{rest...} ;handlers.CreatePet
+        tags: [pets]
+        profiles: [dev]
+        exampleBody: {name: Rex}
+        examples:
+          - status: 201
+            body: created
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := IR{
+		Version:     IRVersion,
+		MuxcVersion: version,
+		SourceFile:  "muxc.yaml",
+		Package:     "routes",
+		Out:         ".",
+		Imports:     []IRImport{{Path: routePackage}, {Path: "example.com/handlers"}},
+		ArgsStyle:   "positional",
+		Router:      "servemux",
+		Args:        []IRDecl{{Name: "prefix", Expr: "string"}},
+		// vars are sorted in declaration order
+		Vars: []IRDecl{{Name: "a", Expr: `handlers.Header("x")`}, {Name: "b", Expr: "handlers.Wrap(a)"}},
+		Routes: []IRRoute{
+			{
+				Name:        "ReadPet",
+				Method:      "GET",
+				Pattern:     "GET /api/pets/{id}",
+				Path:        "/api/pets/{id}",
+				Base:        "/api",
+				Handler:     "handlers.ReadPet(prefix)",
+				Middlewares: []string{"b", "a"},
+				Wildcards:   []IRWildcard{{Name: "id", Type: "int64"}},
+				Tags:        []string{},
+				Profiles:    []string{},
+				Examples:    []IRExample{},
+				Position:    IRPosition{File: "muxc.yaml", Line: 14},
+			},
+			{
+				Name:        "CreatePet",
+				Method:      "POST",
+				Pattern:     "POST /api/pets/{name}/{rest...}",
+				Path:        "/api/pets/{name}/{rest...}",
+				Base:        "/api",
+				Handler:     "handlers.CreatePet",
+				Middlewares: []string{"b"},
+				Wildcards:   []IRWildcard{{Name: "name", Type: "regexp", Regexp: "[a-z]+"}, {Name: "rest", Remainder: true}},
+				Tags:        []string{"pets"},
+				Profiles:    []string{"dev"},
+				ExampleBody: "{\n  \"name\": \"Rex\"\n}",
+				Examples:    []IRExample{{Name: "1", Status: 201, Headers: map[string]string{}, Body: "created"}},
+				Position:    IRPosition{File: "muxc.yaml", Line: 15},
+			},
+		},
+		Profiles: []string{"dev"},
+	}
+	if got := cfg.IR(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got IR\n%+v\nwant\n%+v", got, want)
+	}
+}
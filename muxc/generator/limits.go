@@ -0,0 +1,98 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Limits are the timeout, body size limit and connection deadlines of a route, declared in paths
+// and route groups (inherited by their paths). A zero value disables an inherited limit.
+type Limits struct {
+	Timeout       string `yaml:"timeout"`
+	MaxBodyBytes  *int64 `yaml:"maxBodyBytes"`
+	ReadDeadline  string `yaml:"readDeadline"`
+	WriteDeadline string `yaml:"writeDeadline"`
+}
+
+// DescriptorField is a field of the route descriptor set from the yaml configuration.
+type DescriptorField struct {
+	Name string
+	Expr string
+}
+
+// merge returns the limits overriding the inherited ones with the declared ones.
+func (limits Limits) merge(inherited Limits) Limits {
+	if limits.Timeout == "" {
+		limits.Timeout = inherited.Timeout
+	}
+	if limits.MaxBodyBytes == nil {
+		limits.MaxBodyBytes = inherited.MaxBodyBytes
+	}
+	if limits.ReadDeadline == "" {
+		limits.ReadDeadline = inherited.ReadDeadline
+	}
+	if limits.WriteDeadline == "" {
+		limits.WriteDeadline = inherited.WriteDeadline
+	}
+	return limits
+}
+
+// fields validates the limits, returning the route descriptor fields of the non zero ones.
+func (limits Limits) fields() ([]DescriptorField, error) {
+	fields := []DescriptorField{}
+	duration := func(name string, value string) error {
+		if value == "" {
+			return nil
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return fmt.Errorf("invalid %s '%s', it should be a duration (e.g. 5s)", lowerCamel(name), value)
+		}
+		if d > 0 {
+			fields = append(fields, DescriptorField{Name: name, Expr: durationExpr(d)})
+		}
+		return nil
+	}
+	if err := duration("Timeout", limits.Timeout); err != nil {
+		return nil, err
+	}
+	if limits.MaxBodyBytes != nil {
+		if *limits.MaxBodyBytes < 0 {
+			return nil, fmt.Errorf("invalid maxBodyBytes %d, it should not be negative", *limits.MaxBodyBytes)
+		}
+		if *limits.MaxBodyBytes > 0 {
+			fields = append(fields, DescriptorField{Name: "MaxBodyBytes", Expr: strconv.FormatInt(*limits.MaxBodyBytes, 10)})
+		}
+	}
+	if err := duration("ReadDeadline", limits.ReadDeadline); err != nil {
+		return nil, err
+	}
+	if err := duration("WriteDeadline", limits.WriteDeadline); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// prepareLimits wraps the validator of the routes with a timeout or body size limit with route.Limit,
+// so that they are applied inside the route middlewares (the deadlines are applied by route.Inject).
+func prepareLimits(cfg *Conf) {
+	for _, path := range allPaths(cfg) {
+		limited := false
+		for _, field := range path.Limits {
+			limited = limited || field.Name == "Timeout" || field.Name == "MaxBodyBytes"
+			if field.Name != "MaxBodyBytes" {
+				cfg.addGenImport("time")
+			}
+		}
+		if !limited {
+			continue
+		}
+		limit := fmt.Sprintf("route.Limit(&routeDescriptors[%d])", path.Index)
+		if path.Validator == "" {
+			path.Validator = limit
+		} else {
+			path.Validator = fmt.Sprintf("stack(%s, %s)", path.Validator, limit)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+const maxMacroDepth int = 32
+
+type Macro struct {
+	Name   string
+	Params []string
+	Body   string
+}
+
+// parseMacros parses the macro definitions, declared as name(param1, param2): expression.
+func parseMacros(cfg *Conf) (map[string]Macro, error) {
+	macros := map[string]Macro{}
+	for _, decl := range cfg.Macros {
+		node, err := parser.ParseExpr(decl.Name)
+		call, ok := node.(*ast.CallExpr)
+		if err != nil || !ok || call.Ellipsis.IsValid() {
+			return nil, fmt.Errorf("invalid macro '%s', it should be declared as name(param1, param2, ...)", decl.Name)
+		}
+		name, ok := call.Fun.(*ast.Ident)
+		if !ok {
+			return nil, fmt.Errorf("invalid macro '%s', it should be declared as name(param1, param2, ...)", decl.Name)
+		}
+		macro := Macro{Name: name.Name, Params: []string{}, Body: decl.Expr}
+		for _, arg := range call.Args {
+			param, ok := arg.(*ast.Ident)
+			if !ok || slices.Contains(macro.Params, param.Name) {
+				return nil, fmt.Errorf("invalid macro '%s', its parameters should be distinct identifiers", decl.Name)
+			}
+			macro.Params = append(macro.Params, param.Name)
+		}
+		if _, exists := macros[macro.Name]; exists {
+			return nil, fmt.Errorf("macro '%s' is declared more than once", macro.Name)
+		}
+		if _, isVar := cfg.Vars.Lookup(macro.Name); isVar {
+			return nil, fmt.Errorf("macro '%s' has the same name as a var", macro.Name)
+		}
+		if _, err := parser.ParseExpr(macro.Body); err != nil {
+			return nil, fmt.Errorf("error parsing macro '%s': %w", macro.Name, err)
+		}
+		macros[macro.Name] = macro
+	}
+	return macros, nil
+}
+
+// expand returns the macro body with its parameters replaced by the given arguments.
+func (macro Macro) expand(args []string) (string, error) {
+	fset := token.NewFileSet()
+	node, err := parser.ParseExprFrom(fset, "", macro.Body, 0)
+	if err != nil {
+		return "", err
+	}
+	skip := map[*ast.Ident]bool{}
+	replacements := []replacement{}
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			skip[n.Sel] = true
+		case *ast.KeyValueExpr:
+			if key, ok := n.Key.(*ast.Ident); ok {
+				skip[key] = true
+			}
+		case *ast.Ident:
+			if i := slices.Index(macro.Params, n.Name); i >= 0 && !skip[n] {
+				replacements = append(replacements, replacement{
+					start: fset.Position(n.Pos()).Offset,
+					end:   fset.Position(n.End()).Offset,
+					text:  operand(args[i]),
+				})
+			}
+		}
+		return true
+	})
+	return replace(macro.Body, replacements), nil
+}
+
+type replacement struct {
+	start int
+	end   int
+	text  string
+}
+
+// replace applies non overlapping replacements, sorted by position, to s.
+func replace(s string, replacements []replacement) string {
+	for i := len(replacements) - 1; i >= 0; i-- {
+		s = s[:replacements[i].start] + replacements[i].text + s[replacements[i].end:]
+	}
+	return s
+}
+
+// operand wraps an argument in parentheses when needed to keep its meaning once substituted.
+func operand(arg string) string {
+	node, err := parser.ParseExpr(arg)
+	if err != nil {
+		return arg
+	}
+	switch node.(type) {
+	case *ast.Ident, *ast.BasicLit, *ast.SelectorExpr, *ast.CallExpr, *ast.IndexExpr, *ast.CompositeLit, *ast.ParenExpr:
+		return arg
+	}
+	return "(" + arg + ")"
+}
+
+// expandMacros replaces the macro calls of a go expression by the macro bodies, the
+// arguments of a call and the bodies can call other macros.
+func expandMacros(expr string, macros map[string]Macro) (string, error) {
+	if len(macros) == 0 {
+		return expr, nil
+	}
+	for depth := 0; ; depth++ {
+		fset := token.NewFileSet()
+		node, err := parser.ParseExprFrom(fset, "", expr, 0)
+		if err != nil {
+			return "", fmt.Errorf("error parsing expression '%s': %w", expr, err)
+		}
+		replacements := []replacement{}
+		ast.Inspect(node, func(n ast.Node) bool {
+			if err != nil {
+				return false
+			}
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			name, ok := call.Fun.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			macro, ok := macros[name.Name]
+			if !ok {
+				return true
+			}
+			if len(call.Args) != len(macro.Params) || call.Ellipsis.IsValid() {
+				err = fmt.Errorf("macro '%s' expects %d arguments (%s), got %d", macro.Name, len(macro.Params), strings.Join(macro.Params, ", "), len(call.Args))
+				return false
+			}
+			args := make([]string, len(call.Args))
+			for i, arg := range call.Args {
+				args[i] = expr[fset.Position(arg.Pos()).Offset:fset.Position(arg.End()).Offset]
+			}
+			var expansion string
+			if expansion, err = macro.expand(args); err != nil {
+				return false
+			}
+			replacements = append(replacements, replacement{
+				start: fset.Position(call.Pos()).Offset,
+				end:   fset.Position(call.End()).Offset,
+				text:  expansion,
+			})
+			return false
+		})
+		if err != nil {
+			return "", err
+		}
+		if len(replacements) == 0 {
+			return expr, nil
+		}
+		if depth == maxMacroDepth {
+			return "", fmt.Errorf("too many nested macro calls expanding '%s', macros can't be recursive", expr)
+		}
+		expr = replace(expr, replacements)
+	}
+}
+
+// splitTopLevel splits s by sep, ignoring the separators inside brackets, string and
+// rune literals, so that expressions like header("a", "b") are kept together.
+func splitTopLevel(s string, sep byte) []string {
+	parts := []string{}
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote != '`' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
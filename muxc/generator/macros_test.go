@@ -0,0 +1,89 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpandMacros(t *testing.T) {
+	macros := Decls{
+		{Name: "auth(role)", Expr: "middlewares.Auth(role)"},
+		{Name: "header(name, value)", Expr: "middlewares.SetHeader(name, value)"},
+		{Name: "json()", Expr: `header("Content-Type", "application/json")`},
+		{Name: "say(msg)", Expr: `log("msg: " + msg)`},
+		{Name: "double(x)", Expr: "x * 2"},
+		{Name: "get(name)", Expr: "ctrl.name(name)"},
+		{Name: "opts(Timeout)", Expr: "Options{Timeout: Timeout}"},
+		{Name: "pair(a, b)", Expr: "f(a, b)"},
+		{Name: "loop(x)", Expr: "loop(x)"},
+		{Name: "ping(x)", Expr: "pong(x)"},
+		{Name: "pong(x)", Expr: "ping(x)"},
+	}
+	tests := []struct {
+		expr string
+		want string
+		err  string
+	}{
+		{expr: "handlers.ListPets(ctrl)", want: "handlers.ListPets(ctrl)"},
+		{expr: `auth("admin")`, want: `middlewares.Auth("admin")`},
+		// nested macros, in bodies and arguments
+		{expr: "json()", want: `middlewares.SetHeader("Content-Type", "application/json")`},
+		{expr: `auth(auth("admin"))`, want: `middlewares.Auth(middlewares.Auth("admin"))`},
+		{expr: `header("X-Role", say(json()))`, want: `middlewares.SetHeader("X-Role", log("msg: " + middlewares.SetHeader("Content-Type", "application/json")))`},
+		{expr: `stack(auth("admin"), json())`, want: `stack(middlewares.Auth("admin"), middlewares.SetHeader("Content-Type", "application/json"))`},
+		// argument substitution inside string literals
+		{expr: `say("hi")`, want: `log("msg: " + "hi")`},
+		{expr: `auth("auth(role)")`, want: `middlewares.Auth("auth(role)")`},
+		{expr: "say(`role`)", want: "log(\"msg: \" + `role`)"},
+		// operators, selectors, keys and simultaneous substitution
+		{expr: "double(a + b)", want: "(a + b) * 2"},
+		{expr: "double(a.b)", want: "a.b * 2"},
+		{expr: "get(id)", want: "ctrl.name(id)"},
+		{expr: "opts(5)", want: "Options{Timeout: 5}"},
+		{expr: "pair(b, a)", want: "f(b, a)"},
+		// recursive definitions and wrong calls
+		{expr: "loop(1)", err: "macros can't be recursive"},
+		{expr: "ping(1)", err: "macros can't be recursive"},
+		{expr: "auth(loop(1))", err: "macros can't be recursive"},
+		{expr: "auth()", err: "macro 'auth' expects 1 arguments (role), got 0"},
+		{expr: "pair(a...)", err: "macro 'pair' expects 2 arguments (a, b), got 1"},
+	}
+	parsed, err := parseMacros(&Conf{Macros: macros})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		got, err := expandMacros(test.expr, parsed)
+		switch {
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%s: got error %v, want %q", test.expr, err, test.err)
+		case test.err == "" && err != nil:
+			t.Errorf("%s: %v", test.expr, err)
+		case got != test.want:
+			t.Errorf("%s: got %s, want %s", test.expr, got, test.want)
+		}
+	}
+}
+
+func TestParseMacros(t *testing.T) {
+	tests := []struct {
+		macros Decls
+		vars   Decls
+		err    string
+	}{
+		{macros: Decls{{Name: "auth(role)", Expr: "middlewares.Auth(role)"}}},
+		{macros: Decls{{Name: "auth", Expr: "x"}}, err: "invalid macro 'auth'"},
+		{macros: Decls{{Name: "m.auth(role)", Expr: "x"}}, err: "invalid macro 'm.auth(role)'"},
+		{macros: Decls{{Name: "auth(role, role)", Expr: "x"}}, err: "should be distinct identifiers"},
+		{macros: Decls{{Name: `auth("role")`, Expr: "x"}}, err: "should be distinct identifiers"},
+		{macros: Decls{{Name: "auth(role)", Expr: "x"}, {Name: "auth(a, b)", Expr: "y"}}, err: "macro 'auth' is declared more than once"},
+		{macros: Decls{{Name: "logger()", Expr: "x"}}, vars: Decls{{Name: "logger", Expr: "y"}}, err: "same name as a var"},
+		{macros: Decls{{Name: "auth(role)", Expr: "middlewares.Auth(role"}}, err: "error parsing macro 'auth'"},
+	}
+	for _, test := range tests {
+		_, err := parseMacros(&Conf{Macros: test.macros, Vars: test.vars})
+		if test.err == "" && err != nil || test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+			t.Errorf("%v: got error %v, want %q", test.macros, err, test.err)
+		}
+	}
+}
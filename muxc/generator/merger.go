@@ -0,0 +1,230 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+type MultiYamlFile struct {
+	BaseDir    string
+	SourceFile string
+	FilePath   string
+	FileName   string
+	data       []byte
+	decoded    *yaml.Node
+	includes   []*MultiYamlFile
+	sources    map[*yaml.Node]string
+	dir        string
+	open       func(string) (io.ReadCloser, error)
+}
+
+type Position struct {
+	File string
+	Line int
+}
+
+func (pos Position) String() string {
+	return fmt.Sprintf("%s:%d", pos.File, pos.Line)
+}
+
+// NewMultiYamlFile reads a yaml configuration and its includes, which are opened from basedir.
+func NewMultiYamlFile(sourceFile string, cfgFile io.Reader, basedir string) (*MultiYamlFile, error) {
+	open := func(name string) (io.ReadCloser, error) {
+		return os.Open(name)
+	}
+	file, err := resolveIncludes(path.Base(sourceFile), cfgFile, basedir, open, nil)
+	if err != nil {
+		return nil, err
+	}
+	file.SourceFile = sourceFile
+	file.BaseDir = basedir
+	file.dir, file.open = basedir, open
+	return file, err
+}
+
+// LoadFile reads a yaml configuration file and its includes from disk, generated files are
+// written relative to the directory of the file.
+func LoadFile(name string) (*MultiYamlFile, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewMultiYamlFile(name, f, path.Dir(name))
+}
+
+// Load reads a yaml configuration file and its includes from fsys. The BaseDir of the returned
+// file is empty, it should be set to the directory generated files are written to (and imports
+// inferred from) before calling Generate.
+func Load(fsys fs.FS, name string) (*MultiYamlFile, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	open := func(name string) (io.ReadCloser, error) {
+		return fsys.Open(name)
+	}
+	file, err := resolveIncludes(path.Base(name), f, path.Dir(name), open, nil)
+	if err != nil {
+		return nil, err
+	}
+	file.SourceFile = name
+	file.dir, file.open = path.Dir(name), open
+	return file, nil
+}
+
+// ReadFile reads a file referenced by the yaml configuration, relative to the directory
+// includes are read from.
+func (file *MultiYamlFile) ReadFile(name string) ([]byte, error) {
+	if file.open == nil {
+		return nil, fmt.Errorf("unable to read %s, the yaml configuration was not loaded from a file", name)
+	}
+	f, err := file.open(path.Join(file.dir, name))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+func (yf *MultiYamlFile) GetAllFilePaths() []string {
+	paths := []string{yf.FilePath}
+	for i := range yf.includes {
+		paths = append(paths, yf.includes[i].GetAllFilePaths()...)
+	}
+	return paths
+}
+
+// Resolve decodes all the files and merges the included ones into a single yaml node,
+// keeping track of the file each node comes from.
+func (yf *MultiYamlFile) Resolve() (*yaml.Node, error) {
+	yf.sources = map[*yaml.Node]string{}
+	if err := yf.decode(yf.sources); err != nil {
+		return nil, err
+	}
+	return yf.merge(), nil
+}
+
+// Position returns the file (relative to the base directory) and line a resolved node comes from.
+func (yf *MultiYamlFile) Position(node *yaml.Node) Position {
+	return Position{File: yf.sources[node], Line: node.Line}
+}
+
+func (yf *MultiYamlFile) merge() *yaml.Node {
+	for i := range yf.includes {
+		yf.decoded = mergeNodes(yf.decoded, yf.includes[i].merge())
+	}
+	return yf.decoded
+}
+
+func (yf *MultiYamlFile) decode(sources map[*yaml.Node]string) error {
+	document := &yaml.Node{}
+	if err := yaml.Unmarshal(yf.data, document); err != nil {
+		return fmt.Errorf("error decoding %s: %w", yf.FileName, err)
+	}
+	if len(document.Content) == 0 {
+		yf.decoded = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	} else if yf.decoded = document.Content[0]; yf.decoded.Kind != yaml.MappingNode {
+		return fmt.Errorf("error decoding %s: expected a mapping at line %d", yf.FileName, yf.decoded.Line)
+	}
+	setSource(yf.decoded, yf.FileName, sources)
+	for i := range yf.includes {
+		if err := yf.includes[i].decode(sources); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setSource(node *yaml.Node, file string, sources map[*yaml.Node]string) {
+	sources[node] = file
+	for i := range node.Content {
+		setSource(node.Content[i], file, sources)
+	}
+}
+
+var includeMatcher *regexp.Regexp = regexp.MustCompile(`^!include "*([^"]+)"*$`)
+
+func resolveIncludes(filename string, file io.Reader, basedir string, open func(name string) (io.ReadCloser, error), alreadyIncluded []string) (*MultiYamlFile, error) {
+	if alreadyIncluded == nil {
+		alreadyIncluded = []string{}
+	}
+	buffer := &bytes.Buffer{}
+	filePath := path.Join(basedir, filename)
+	if slices.Contains(alreadyIncluded, filePath) {
+		return nil, fmt.Errorf("include loop detected, '%s' is already included", filePath)
+	}
+	alreadyIncluded = append(alreadyIncluded, filePath)
+	scanner := bufio.NewScanner(file)
+	includes := []string{}
+	for scanner.Scan() {
+		line := scanner.Text()
+		matches := includeMatcher.FindStringSubmatch(line)
+		if len(matches) == 2 {
+			includes = append(includes, matches[1])
+			buffer.WriteString("\n")
+		} else {
+			buffer.WriteString(line + "\n")
+		}
+	}
+	yamlFile := &MultiYamlFile{
+		FilePath: filePath,
+		FileName: filename,
+		data:     buffer.Bytes(),
+		includes: []*MultiYamlFile{},
+	}
+	for i := range includes {
+		f, err := open(path.Join(basedir, includes[i]))
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		included, err := resolveIncludes(includes[i], f, basedir, open, alreadyIncluded)
+		if err != nil {
+			return nil, err
+		}
+		yamlFile.includes = append(yamlFile.includes, included)
+	}
+	return yamlFile, nil
+}
+
+// mergeNodes merges two mapping nodes, nested mappings are merged, sequences are
+// appended and any other value of node2 replaces the one of node1.
+func mergeNodes(node1, node2 *yaml.Node) *yaml.Node {
+	for i := 0; i+1 < len(node2.Content); i += 2 {
+		key2, value2 := node2.Content[i], node2.Content[i+1]
+		j := mappingIndex(node1, key2.Value)
+		if j == -1 {
+			node1.Content = append(node1.Content, key2, value2)
+			continue
+		}
+		value1 := node1.Content[j+1]
+		if value1.Kind == yaml.MappingNode && value2.Kind == yaml.MappingNode {
+			node1.Content[j+1] = mergeNodes(value1, value2)
+		} else if value1.Kind == yaml.SequenceNode && value2.Kind == yaml.SequenceNode {
+			value1.Content = append(value1.Content, value2.Content...)
+		} else {
+			node1.Content[j+1] = value2
+		}
+	}
+	return node1
+}
+
+func mappingIndex(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"path"
+)
+
+type MockConf struct {
+	Package string `yaml:"package"`
+	Out     string `yaml:"out"`
+}
+
+type MockFile struct {
+	Package     string
+	MuxcVersion string
+	SourceFile  string
+	Routes      []MockRoute
+}
+
+type MockRoute struct {
+	Name     string
+	Pattern  string
+	Examples []ExampleResponse
+}
+
+func buildMockFile(cfg *Conf) (*MockFile, error) {
+	if err := checkRouteNames(cfg); err != nil {
+		return nil, err
+	}
+	if cfg.Mock.Package == "" {
+		return nil, fmt.Errorf("mock package is not configured")
+	}
+	file := &MockFile{
+		Package:     cfg.Mock.Package,
+		MuxcVersion: cfg.MuxcVersion,
+		SourceFile:  cfg.SourceFile,
+		Routes:      []MockRoute{},
+	}
+	for i := range cfg.Routes {
+		for _, path := range cfg.Routes[i].ParsedPaths {
+			if path.builtin() {
+				continue
+			}
+			if path.RouteName() == "NewMux" {
+				return nil, fmt.Errorf("route '%s' can not be named NewMux, set a different name with the name field", path.FullPattern)
+			}
+			file.Routes = append(file.Routes, MockRoute{Name: path.RouteName(), Pattern: path.MuxPattern, Examples: path.Examples})
+		}
+	}
+	return file, nil
+}
+
+// RenderMock writes the mock handlers file to w.
+func (cfg *Conf) RenderMock(w io.Writer) error {
+	if cfg.mockFile == nil {
+		return fmt.Errorf("mock handlers are not configured")
+	}
+	return renderGo(w, "mock.go.tmpl", cfg.mockFile)
+}
+
+func createMockFile(cfg *Conf, basedir string) error {
+	if cfg.Mock.Out == "" {
+		return fmt.Errorf("mock out directory is not configured")
+	}
+	return writeFile(path.Join(basedir, cfg.Mock.Out), "mock.go", cfg.RenderMock)
+}
@@ -0,0 +1,581 @@
+// Package generator parses muxc yaml configurations and generates the routes file, and any other
+// configured output, from them. Configurations are loaded with Load or LoadFile, Parse returns the
+// validated model, which can be rendered to any writer, and Generate writes every file to disk.
+package generator
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+const version string = "v1.0.0"
+
+const routePackage string = "github.com/enolgor/muxc/middlewares/route"
+
+type Conf struct {
+	Package        string          `yaml:"package"`
+	Out            string          `yaml:"out"`
+	Imports        []string        `yaml:"imports"`
+	Args           Decls           `yaml:"args"`
+	ArgsStyle      string          `yaml:"argsStyle"`
+	Router         string          `yaml:"router"`
+	Routes         []Routes        `yaml:"routes"`
+	Vars           Decls           `yaml:"vars"`
+	Macros         Decls           `yaml:"macros"`
+	ReturnErrors   bool            `yaml:"returnErrors"`
+	Client         *ClientConf     `yaml:"client"`
+	TypeScript     *TypeScriptConf `yaml:"typescript"`
+	Postman        *PostmanConf    `yaml:"postman"`
+	HTTPFile       *HTTPFileConf   `yaml:"httpFile"`
+	Docs           *DocsConf       `yaml:"docs"`
+	Mock           *MockConf       `yaml:"mock"`
+	Tests          *TestsConf      `yaml:"tests"`
+	Plugins        []PluginConf    `yaml:"plugins"`
+	PackageName    string
+	MuxcVersion    string
+	SourceFile     string
+	OrderedVars    Decls
+	Profiles       []string
+	routesFile     RoutesFile
+	packages       *packageIndex
+	clientFile     *ClientFile
+	typeScriptFile *TypeScriptFile
+	mockFile       *MockFile
+	testsFile      *TestsFile
+}
+
+// RoutesFile is the model of the generated routes file, rendered along with the configuration.
+type RoutesFile struct {
+	Accessors     []Accessor
+	URLBuilders   []URLBuilder
+	ConfigureVars Decls
+	Registrations []Registration
+	ProfileRoutes []ProfileRoutes
+	Builders      bool
+	Params        []Param
+	RouterType    string
+	Regexps       []string
+	GenImports    []string
+	StdImports    []string
+	UsedImports   []string
+}
+
+// RoutePath is either the semi-colon separated path definition or a mapping with
+// the path definition under the path key and optional route metadata.
+type RoutePath struct {
+	Path        string        `yaml:"path"`
+	Name        string        `yaml:"name"`
+	In          string        `yaml:"in"`
+	Out         string        `yaml:"out"`
+	Tags        []string      `yaml:"tags"`
+	Profiles    []string      `yaml:"profiles"`
+	Override    []string      `yaml:"override"`
+	Description string        `yaml:"description"`
+	Deprecated  string        `yaml:"deprecated"`
+	ExampleBody yaml.Node     `yaml:"exampleBody"`
+	Examples    []Example     `yaml:"examples"`
+	Static      *StaticConf   `yaml:"static"`
+	Proxy       *ProxyConf    `yaml:"proxy"`
+	Redirect    *RedirectConf `yaml:"redirect"`
+	Limits      `yaml:",inline"`
+	node        *yaml.Node
+}
+
+func (rp *RoutePath) UnmarshalYAML(node *yaml.Node) error {
+	rp.node = node
+	if node.Kind == yaml.ScalarNode {
+		rp.Path = node.Value
+		return nil
+	}
+	type routePath RoutePath
+	return node.Decode((*routePath)(rp))
+}
+
+type ParsedPath struct {
+	Method        string
+	Pattern       string
+	Handler       string
+	Middlewares   []string
+	Wildcards     []Wildcard
+	Validator     string
+	Name          string
+	In            string
+	Out           string
+	FullPattern   string
+	MuxPattern    string
+	RouterPattern string
+	Stack         []string
+	Tags          []string
+	Profiles      []string
+	Condition     string
+	Exclude       []string
+	Override      []string
+	ExampleBody   string
+	Examples      []ExampleResponse
+	Description   string
+	Deprecated    bool
+	Deprecation   string
+	Static        *StaticConf
+	Proxy         *ProxyConf
+	Redirect      *RedirectConf
+	Builder       string
+	BuiltHandler  string
+	Limits        []DescriptorField
+	Position      Position
+	Index         int
+}
+
+// RouteName returns the route name, either the one explicitly set or the one derived
+// from the handler expression, e.g. handlers.ReadPet(ctrl) is named ReadPet. Static,
+// proxy and redirect routes are only named explicitly.
+func (path ParsedPath) RouteName() string {
+	if path.Name != "" || path.builtin() {
+		return path.Name
+	}
+	name, _, _ := strings.Cut(path.Handler, "(")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ""
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// checkRouteNames verifies that every route has a unique name, as required by the
+// generators that produce one function per route (static, proxy and redirect routes are skipped by them).
+func checkRouteNames(cfg *Conf) error {
+	names := map[string]string{}
+	for i := range cfg.Routes {
+		for _, path := range cfg.Routes[i].ParsedPaths {
+			name := path.RouteName()
+			if path.builtin() && name == "" {
+				continue
+			}
+			if name == "" {
+				return fmt.Errorf("unable to derive a name for route '%s', set one with the name field", path.FullPattern)
+			}
+			if other, exists := names[name]; exists {
+				return fmt.Errorf("routes '%s' and '%s' are both named %s, set a different name with the name field", other, path.FullPattern, name)
+			}
+			names[name] = path.FullPattern
+		}
+	}
+	return nil
+}
+
+// stack returns the middlewares of the path, outermost first: the ones inherited from the
+// route group (or the override list) minus the excluded ones, followed by the path ones.
+func (path ParsedPath) stack(use []string) ([]string, error) {
+	inherited := use
+	if path.Override != nil {
+		inherited = path.Override
+	}
+	stack := []string{}
+	for _, excluded := range path.Exclude {
+		if !slices.ContainsFunc(inherited, func(mw string) bool { return sameExpr(mw, excluded) }) {
+			return nil, fmt.Errorf("excluded middleware '%s' is not inherited from the route group", excluded)
+		}
+	}
+	for _, mw := range inherited {
+		if !slices.ContainsFunc(path.Exclude, func(excluded string) bool { return sameExpr(mw, excluded) }) {
+			stack = append(stack, mw)
+		}
+	}
+	return append(stack, path.Middlewares...), nil
+}
+
+// sameExpr compares two expressions ignoring whitespace.
+func sameExpr(expr1, expr2 string) bool {
+	return strings.Join(strings.Fields(expr1), "") == strings.Join(strings.Fields(expr2), "")
+}
+
+func isEmpty(s string) bool {
+	return s == ""
+}
+
+func (rp RoutePath) Parse() (parsed ParsedPath, err error) {
+	parts := splitTopLevel(rp.Path, ';')
+	if len(parts) < 2 && rp.Static == nil && rp.Proxy == nil && rp.Redirect == nil {
+		err = fmt.Errorf("invalid path '%s', it should contain at least pattern and handler parts", rp.Path)
+		return
+	}
+	if len(parts) > 3 {
+		err = fmt.Errorf("invalid path '%s', middlewares should be comma separated", rp.Path)
+		return
+	}
+	parsed.Pattern = strings.TrimSpace(parts[0])
+	pattern_parts := strings.Split(parsed.Pattern, " ")
+	pattern_parts = slices.DeleteFunc(pattern_parts, isEmpty)
+	if len(pattern_parts) > 2 {
+		err = fmt.Errorf("invalid path '%s', pattern has more than 2 parts", rp.Path)
+		return
+	}
+	if len(pattern_parts) == 2 {
+		parsed.Method = pattern_parts[0]
+		parsed.Pattern = pattern_parts[1]
+	}
+	if parsed.Pattern, parsed.Wildcards, err = parsePattern(parsed.Pattern); err != nil {
+		return
+	}
+	if len(parts) > 1 {
+		parsed.Handler = strings.TrimSpace(parts[1])
+	}
+	if rp.Static != nil || rp.Proxy != nil || rp.Redirect != nil {
+		if err = parsed.parseBuiltin(rp); err != nil {
+			return
+		}
+	}
+	if len(parts) == 3 {
+		mwparts := splitTopLevel(parts[2], ',')
+		parsed.Middlewares = make([]string, 0, len(mwparts))
+		for i := range mwparts {
+			mw := strings.TrimSpace(mwparts[i])
+			if excluded, found := strings.CutPrefix(mw, "!"); found {
+				parsed.Exclude = append(parsed.Exclude, strings.TrimSpace(excluded))
+				continue
+			}
+			parsed.Middlewares = append(parsed.Middlewares, mw)
+		}
+	}
+	parsed.Override = rp.Override
+	parsed.Name = rp.Name
+	parsed.In = rp.In
+	parsed.Out = rp.Out
+	parsed.Tags = rp.Tags
+	parsed.Profiles = rp.Profiles
+	parsed.Description = strings.TrimSpace(rp.Description)
+	switch deprecated := strings.TrimSpace(rp.Deprecated); deprecated {
+	case "", "false":
+	case "true":
+		parsed.Deprecated = true
+	default:
+		parsed.Deprecated, parsed.Deprecation = true, deprecated
+	}
+	if parsed.ExampleBody, err = exampleBody(&rp.ExampleBody); err != nil {
+		err = fmt.Errorf("invalid exampleBody: %w", err)
+	}
+	return
+}
+
+type Routes struct {
+	Use         []string    `yaml:"use"`
+	Base        string      `yaml:"base"`
+	Description string      `yaml:"description"`
+	Tags        []string    `yaml:"tags"`
+	Profiles    []string    `yaml:"profiles"`
+	Paths       []RoutePath `yaml:"paths"`
+	Limits      `yaml:",inline"`
+	ParsedPaths []ParsedPath
+}
+
+var (
+	//go:embed templates
+	tmplFS    embed.FS
+	templates *template.Template
+)
+
+func init() {
+	templates = template.Must(
+		template.New("muxc").
+			Funcs(template.FuncMap{
+				"Join":  strings.Join,
+				"Quote": strconv.Quote,
+				"WildcardPattern": func(typ string) string {
+					return Wildcard{Type: typ}.Pattern()
+				},
+				"Slice": func(s string) []string {
+					return []string{s}
+				},
+				"Append": func(slice1 []string, slice2 []string) []string {
+					return append(slice1, slice2...)
+				},
+				"Reverse": func(s []string) []string {
+					copy := make([]string, len(s))
+					for i := range s {
+						copy[len(s)-1-i] = s[i]
+					}
+					return copy
+				},
+				"Contains": slices.Contains[[]string],
+			}).
+			ParseFS(tmplFS, "templates/*.tmpl"),
+	)
+}
+
+// ParseRoutes resolves and validates the routes and vars of the yaml configuration, without
+// preparing the generated files (e.g. without resolving imports).
+func ParseRoutes(yamlFile *MultiYamlFile) (*Conf, []string, error) {
+	cfg, err := parseConf(yamlFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	warnings, err := checkConflicts(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	varWarnings, err := orderVars(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, append(warnings, varWarnings...), nil
+}
+
+// Parse resolves and validates the yaml configuration, returning the model the generated files are
+// rendered from and warnings about routes that are valid but probably wrong.
+func Parse(yamlFile *MultiYamlFile) (*Conf, []string, error) {
+	cfg, warnings, err := ParseRoutes(yamlFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = prepareParams(cfg); err != nil {
+		return nil, nil, err
+	}
+	if err = prepareProfiles(cfg); err != nil {
+		return nil, nil, err
+	}
+	prepareLimits(cfg)
+	prepareBuilders(cfg)
+	if err = prepareRouter(cfg); err != nil {
+		return nil, nil, err
+	}
+	if err = prepareRegistrations(cfg); err != nil {
+		return nil, nil, err
+	}
+	types := make([]string, len(cfg.Args))
+	for i := range cfg.Args {
+		types[i] = cfg.Args[i].Expr
+	}
+	refs, err := routeQualifiers(cfg, types, cfg.routesFile.ConfigureVars, cfg.routesFile.Registrations)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.routesFile.UsedImports, err = resolveImports(cfg, yamlFile.BaseDir, refs, cfg.routesFile.GenImports); err != nil {
+		return nil, nil, err
+	}
+	if !slices.Contains(cfg.routesFile.UsedImports, strconv.Quote(routePackage)) {
+		cfg.routesFile.UsedImports = append([]string{strconv.Quote(routePackage)}, cfg.routesFile.UsedImports...)
+	}
+	addBuiltinImports(cfg)
+	addRouterImport(cfg)
+	cfg.routesFile.StdImports, cfg.routesFile.UsedImports = splitStdImports(cfg.routesFile.UsedImports)
+	if err = prepareProfileImports(cfg, yamlFile.BaseDir); err != nil {
+		return nil, nil, err
+	}
+	cfg.SourceFile = path.Base(yamlFile.SourceFile)
+	cfg.MuxcVersion = version
+	if cfg.Client != nil {
+		if cfg.clientFile, err = buildClientFile(cfg, yamlFile.BaseDir); err != nil {
+			return nil, nil, fmt.Errorf("error building client: %w", err)
+		}
+	}
+	if cfg.TypeScript != nil {
+		if cfg.typeScriptFile, err = buildTypeScriptFile(cfg); err != nil {
+			return nil, nil, fmt.Errorf("error building typescript client: %w", err)
+		}
+	}
+	if cfg.Mock != nil {
+		if cfg.mockFile, err = buildMockFile(cfg); err != nil {
+			return nil, nil, fmt.Errorf("error building mock handlers: %w", err)
+		}
+	}
+	if cfg.Tests != nil {
+		var testsWarnings []string
+		if cfg.testsFile, testsWarnings, err = buildTestsFile(cfg, yamlFile.BaseDir); err != nil {
+			return nil, nil, fmt.Errorf("error building tests: %w", err)
+		}
+		warnings = append(warnings, testsWarnings...)
+	}
+	return cfg, warnings, nil
+}
+
+// Generate writes the routes file (and any other configured output) relative to the base
+// directory of the yaml configuration, returning the warnings of Parse.
+func Generate(yamlFile *MultiYamlFile) ([]string, error) {
+	cfg, warnings, err := Parse(yamlFile)
+	if err != nil {
+		return nil, err
+	}
+	if err = createRoutesFile(cfg, yamlFile.BaseDir); err != nil {
+		return nil, err
+	}
+	if err = createProfileFiles(cfg, yamlFile.BaseDir); err != nil {
+		return nil, err
+	}
+	if cfg.Tests != nil {
+		if err = createTestsFile(cfg, yamlFile.BaseDir); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Client != nil {
+		if err = createClientFile(cfg, yamlFile.BaseDir); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.TypeScript != nil {
+		if err = createTypeScriptFile(cfg, yamlFile.BaseDir); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Postman != nil {
+		if err = createPostmanFile(cfg, yamlFile.BaseDir); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.HTTPFile != nil {
+		if err = createHTTPFile(cfg, yamlFile.BaseDir); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Mock != nil {
+		if err = createMockFile(cfg, yamlFile.BaseDir); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Docs != nil {
+		if err = createDocsFile(cfg, yamlFile.BaseDir); err != nil {
+			return nil, err
+		}
+	}
+	pluginWarnings, err := runPlugins(cfg, yamlFile.BaseDir)
+	if err != nil {
+		return nil, err
+	}
+	return append(warnings, pluginWarnings...), nil
+}
+
+func parseConf(yamlFile *MultiYamlFile) (*Conf, error) {
+	node, err := yamlFile.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Conf{}
+	if err = node.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("error decoding yaml file: %w", err)
+	}
+	macros, err := parseMacros(cfg)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cfg.Vars {
+		if cfg.Vars[i].Expr, err = expandMacros(cfg.Vars[i].Expr, macros); err != nil {
+			return nil, fmt.Errorf("error expanding var '%s': %w", cfg.Vars[i].Name, err)
+		}
+	}
+	index := 0
+	for i := range cfg.Routes {
+		var baseWildcards []Wildcard
+		if cfg.Routes[i].Base, baseWildcards, err = parsePattern(cfg.Routes[i].Base); err != nil {
+			return nil, fmt.Errorf("error parsing route base '%s': %w", cfg.Routes[i].Base, err)
+		}
+		cfg.Routes[i].ParsedPaths = make([]ParsedPath, len(cfg.Routes[i].Paths))
+		for j := range cfg.Routes[i].Paths {
+			position := yamlFile.Position(cfg.Routes[i].Paths[j].node)
+			if cfg.Routes[i].ParsedPaths[j], err = cfg.Routes[i].Paths[j].Parse(); err != nil {
+				return nil, fmt.Errorf("error parsing route path '%s' (%s): %w", cfg.Routes[i].Paths[j].Path, position, err)
+			}
+			cfg.Routes[i].ParsedPaths[j].Position = position
+			if cfg.Routes[i].ParsedPaths[j].Examples, err = parseExamples(cfg.Routes[i].Paths[j].Examples, yamlFile.ReadFile); err != nil {
+				return nil, fmt.Errorf("error parsing route path '%s' (%s): %w", cfg.Routes[i].Paths[j].Path, position, err)
+			}
+			cfg.Routes[i].ParsedPaths[j].Index = index
+			index++
+			cfg.Routes[i].ParsedPaths[j].FullPattern = cfg.Routes[i].Base + cfg.Routes[i].ParsedPaths[j].Pattern
+			cfg.Routes[i].ParsedPaths[j].MuxPattern = cfg.Routes[i].ParsedPaths[j].FullPattern
+			if cfg.Routes[i].ParsedPaths[j].Method != "" {
+				cfg.Routes[i].ParsedPaths[j].MuxPattern = cfg.Routes[i].ParsedPaths[j].Method + " " + cfg.Routes[i].ParsedPaths[j].FullPattern
+			}
+			if cfg.Routes[i].ParsedPaths[j].Stack, err = cfg.Routes[i].ParsedPaths[j].stack(cfg.Routes[i].Use); err != nil {
+				return nil, fmt.Errorf("error parsing route path '%s' (%s): %w", cfg.Routes[i].Paths[j].Path, position, err)
+			}
+			if !cfg.Routes[i].ParsedPaths[j].builtin() {
+				if cfg.Routes[i].ParsedPaths[j].Handler, err = expandMacros(cfg.Routes[i].ParsedPaths[j].Handler, macros); err != nil {
+					return nil, fmt.Errorf("error expanding route '%s' (%s): %w", cfg.Routes[i].ParsedPaths[j].MuxPattern, position, err)
+				}
+			}
+			for k := range cfg.Routes[i].ParsedPaths[j].Stack {
+				if cfg.Routes[i].ParsedPaths[j].Stack[k], err = expandMacros(cfg.Routes[i].ParsedPaths[j].Stack[k], macros); err != nil {
+					return nil, fmt.Errorf("error expanding route '%s' (%s): %w", cfg.Routes[i].ParsedPaths[j].MuxPattern, position, err)
+				}
+			}
+			cfg.Routes[i].ParsedPaths[j].Tags = append(slices.Clone(cfg.Routes[i].Tags), cfg.Routes[i].ParsedPaths[j].Tags...)
+			if cfg.Routes[i].ParsedPaths[j].Profiles == nil {
+				cfg.Routes[i].ParsedPaths[j].Profiles = cfg.Routes[i].Profiles
+			}
+			if cfg.Routes[i].ParsedPaths[j].Limits, err = cfg.Routes[i].Paths[j].Limits.merge(cfg.Routes[i].Limits).fields(); err != nil {
+				return nil, fmt.Errorf("error parsing route path '%s' (%s): %w", cfg.Routes[i].Paths[j].Path, position, err)
+			}
+			for k := range cfg.Routes[i].ParsedPaths[j].Wildcards {
+				if cfg.Routes[i].ParsedPaths[j].Wildcards[k].Segment == "" {
+					cfg.Routes[i].ParsedPaths[j].Wildcards[k].Segment = lastLiteral(cfg.Routes[i].Base)
+				}
+			}
+			cfg.Routes[i].ParsedPaths[j].Wildcards = append(slices.Clone(baseWildcards), cfg.Routes[i].ParsedPaths[j].Wildcards...)
+			if cfg.Routes[i].ParsedPaths[j].builtin() {
+				if cfg.Routes[i].ParsedPaths[j].Static != nil && len(baseWildcards) > 0 {
+					return nil, fmt.Errorf("static route '%s' (%s) can't have a base with wildcards", cfg.Routes[i].ParsedPaths[j].MuxPattern, position)
+				}
+				if cfg.Routes[i].ParsedPaths[j].Handler, err = builtinHandler(cfg.Routes[i].ParsedPaths[j]); err != nil {
+					return nil, fmt.Errorf("error parsing route path '%s' (%s): %w", cfg.Routes[i].Paths[j].Path, position, err)
+				}
+			}
+		}
+	}
+	if err = prepareWildcards(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// RenderRoutes writes the routes file to w.
+func (cfg *Conf) RenderRoutes(w io.Writer) error {
+	return renderGo(w, "routes.go.tmpl", struct {
+		*Conf
+		*RoutesFile
+	}{cfg, &cfg.routesFile})
+}
+
+// renderGo executes a template generating go code and writes it formatted to w.
+func renderGo(w io.Writer, name string, data any) error {
+	buffer := &bytes.Buffer{}
+	if err := templates.ExecuteTemplate(buffer, name, data); err != nil {
+		return err
+	}
+	source, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return fmt.Errorf("error formatting generated code: %w", err)
+	}
+	_, err = w.Write(source)
+	return err
+}
+
+// writeFile renders a generated file into dir, creating the directory if needed.
+func writeFile(dir string, name string, render func(w io.Writer) error) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return fmt.Errorf("error creating %s directory: %w", dir, err)
+	}
+	buffer := &bytes.Buffer{}
+	if err := render(buffer); err != nil {
+		return fmt.Errorf("error generating %s file: %w", name, err)
+	}
+	if err := os.WriteFile(path.Join(dir, name), buffer.Bytes(), os.ModePerm); err != nil {
+		return fmt.Errorf("error writing %s file: %w", name, err)
+	}
+	return nil
+}
+
+func createRoutesFile(cfg *Conf, basedir string) error {
+	return writeFile(path.Join(basedir, cfg.Out), "routes.go", cfg.RenderRoutes)
+}
@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestStack(t *testing.T) {
+	const group = "routes:\n  - use:\n      - auth\n      - logger(\"api\")\n    paths:\n"
+	tests := []struct {
+		name string
+		path string
+		want []string
+		err  string
+	}{
+		{name: "inherited", path: "      - GET /pets ;handlers.List ;cache\n", want: []string{"auth", `logger("api")`, "cache"}},
+		{name: "excluded", path: "      - GET /pets ;handlers.List ;!auth, cache\n", want: []string{`logger("api")`, "cache"}},
+		// exclusions are compared ignoring whitespace
+		{name: "excluded call", path: "      - GET /pets ;handlers.List ;! logger( \"api\" )\n", want: []string{"auth"}},
+		{name: "all excluded", path: "      - GET /pets ;handlers.List ;!auth, !logger(\"api\")\n", want: []string{}},
+		{
+			name: "override",
+			path: "      - path: GET /pets ;handlers.List ;cache\n        override:\n          - metrics\n",
+			want: []string{"metrics", "cache"},
+		},
+		{
+			name: "empty override",
+			path: "      - path: GET /pets ;handlers.List ;cache\n        override: []\n",
+			want: []string{"cache"},
+		},
+		{
+			name: "excluded from the override",
+			path: "      - path: GET /pets ;handlers.List ;!metrics\n        override:\n          - metrics\n          - auth\n",
+			want: []string{"auth"},
+		},
+		{
+			name: "not inherited",
+			path: "      - GET /pets ;handlers.List ;!cache\n",
+			err:  "excluded middleware 'cache' is not inherited from the route group",
+		},
+		{
+			name: "replaced by the override",
+			path: "      - path: GET /pets ;handlers.List ;!auth\n        override:\n          - metrics\n",
+			err:  "excluded middleware 'auth' is not inherited from the route group",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg, err := parseTest(t, group+test.path)
+			switch {
+			case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+				t.Errorf("got error %v, want %q", err, test.err)
+			case test.err == "" && err != nil:
+				t.Error(err)
+			case test.err == "" && !slices.Equal(cfg.Routes[0].ParsedPaths[0].Stack, test.want):
+				t.Errorf("got stack %q, want %q", cfg.Routes[0].ParsedPaths[0].Stack, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// PluginConf declares an external generator, the muxc-gen-<name> executable found in the PATH
+// (or the one at path, relative to the yaml file) is run with a PluginRequest on its standard
+// input and writes a PluginResponse to its standard output.
+type PluginConf struct {
+	Name    string            `yaml:"name"`
+	Path    string            `yaml:"path"`
+	Out     string            `yaml:"out"`
+	Options map[string]string `yaml:"options"`
+}
+
+type PluginRequest struct {
+	IR      IR                `json:"ir"`
+	Options map[string]string `json:"options"`
+}
+
+// PluginResponse holds the files to write, with names relative to the plugin out directory,
+// or an error message if the plugin failed.
+type PluginResponse struct {
+	Files    []PluginFile `json:"files"`
+	Warnings []string     `json:"warnings"`
+	Error    string       `json:"error"`
+}
+
+type PluginFile struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// RunPlugin runs a plugin with the intermediate representation of the configuration, basedir
+// is the directory plugin paths are relative to.
+func (cfg *Conf) RunPlugin(plugin PluginConf, basedir string) (*PluginResponse, error) {
+	if plugin.Name == "" {
+		return nil, fmt.Errorf("plugin name is not configured")
+	}
+	executable := plugin.Path
+	if executable == "" {
+		var err error
+		if executable, err = exec.LookPath("muxc-gen-" + plugin.Name); err != nil {
+			return nil, fmt.Errorf("plugin '%s' not found: %w", plugin.Name, err)
+		}
+	} else if !filepath.IsAbs(executable) {
+		executable = filepath.Join(basedir, executable)
+	}
+	options := plugin.Options
+	if options == nil {
+		options = map[string]string{}
+	}
+	request, err := json.Marshal(PluginRequest{IR: cfg.IR(), Options: options})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding plugin '%s' request: %w", plugin.Name, err)
+	}
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd := exec.Command(executable)
+	cmd.Dir = basedir
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("plugin '%s' failed: %w: %s", plugin.Name, err, strings.TrimSpace(stderr.String()))
+	}
+	response := &PluginResponse{}
+	if err := json.Unmarshal(stdout.Bytes(), response); err != nil {
+		return nil, fmt.Errorf("error decoding plugin '%s' response: %w", plugin.Name, err)
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("plugin '%s' failed: %s", plugin.Name, response.Error)
+	}
+	for _, file := range response.Files {
+		if !filepath.IsLocal(file.Name) {
+			return nil, fmt.Errorf("plugin '%s' returned a file outside of its out directory: %s", plugin.Name, file.Name)
+		}
+	}
+	return response, nil
+}
+
+func runPlugins(cfg *Conf, basedir string) ([]string, error) {
+	warnings := []string{}
+	for _, plugin := range cfg.Plugins {
+		response, err := cfg.RunPlugin(plugin, basedir)
+		if err != nil {
+			return nil, err
+		}
+		for _, warning := range response.Warnings {
+			warnings = append(warnings, fmt.Sprintf("plugin '%s': %s", plugin.Name, warning))
+		}
+		for _, file := range response.Files {
+			name := path.Join(basedir, plugin.Out, file.Name)
+			if err := os.MkdirAll(path.Dir(name), os.ModePerm); err != nil {
+				return nil, fmt.Errorf("error creating %s directory: %w", path.Dir(name), err)
+			}
+			if err := os.WriteFile(name, []byte(file.Content), os.ModePerm); err != nil {
+				return nil, fmt.Errorf("error writing %s file: %w", name, err)
+			}
+		}
+	}
+	return warnings, nil
+}
@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunPlugin(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	cfg, err := parseTest(t, "routes:\n  - paths:\n      - GET /pets ;handlers.List\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	// every plugin checks that it gets the request and writes its response
+	plugin := func(name string, response string) string {
+		script := "#!/bin/sh\ngrep -q '\"pattern\":\"GET /pets\"' || exit 3\ncat <<'EOF'\n" + response + "\nEOF\n"
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		return name
+	}
+	tests := []struct {
+		plugin PluginConf
+		files  []string
+		err    string
+	}{
+		{
+			plugin: PluginConf{Name: "ok", Path: plugin("ok", `{"files": [{"name": "routes.txt"}, {"name": "nested/routes.txt"}], "warnings": ["careful"]}`)},
+			files:  []string{"routes.txt", "nested/routes.txt"},
+		},
+		{
+			plugin: PluginConf{Name: "parent", Path: plugin("parent", `{"files": [{"name": "../routes.txt"}]}`)},
+			err:    "plugin 'parent' returned a file outside of its out directory: ../routes.txt",
+		},
+		{
+			plugin: PluginConf{Name: "absolute", Path: plugin("absolute", `{"files": [{"name": "/tmp/routes.txt"}]}`)},
+			err:    "plugin 'absolute' returned a file outside of its out directory: /tmp/routes.txt",
+		},
+		{
+			plugin: PluginConf{Name: "failed", Path: plugin("failed", `{"error": "unsupported router"}`)},
+			err:    "plugin 'failed' failed: unsupported router",
+		},
+		{
+			plugin: PluginConf{Name: "invalid", Path: plugin("invalid", `not json`)},
+			err:    "error decoding plugin 'invalid' response",
+		},
+		{
+			plugin: PluginConf{Name: "missing-muxc-test-plugin"},
+			err:    "plugin 'missing-muxc-test-plugin' not found",
+		},
+		{
+			plugin: PluginConf{},
+			err:    "plugin name is not configured",
+		},
+	}
+	for _, test := range tests {
+		response, err := cfg.RunPlugin(test.plugin, dir)
+		switch {
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%s: got error %v, want %q", test.plugin.Name, err, test.err)
+		case test.err == "" && err != nil:
+			t.Errorf("%s: %v", test.plugin.Name, err)
+		case test.err == "" && len(response.Files) != len(test.files):
+			t.Errorf("%s: got %d files, want %d", test.plugin.Name, len(response.Files), len(test.files))
+		}
+	}
+}
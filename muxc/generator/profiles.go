@@ -0,0 +1,312 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type ProfileFile struct {
+	*ProfileRoutes
+	Package      string
+	MuxcVersion  string
+	SourceFile   string
+	Const        string
+	Enabled      bool
+	RouterType   string
+	ReturnErrors bool
+	StdImports   []string
+	UsedImports  []string
+}
+
+// Registration is the registration of a route by ConfigureMux or by the configure function of
+// a profile, skipped unless the guard condition (if any) holds.
+type Registration struct {
+	Router       string
+	ReturnErrors bool
+	Guard        string
+	Path         *ParsedPath
+}
+
+// ProfileRoutes are the routes of a profile, registered by the configure function declared in the
+// file built with the profile build tag. The file built without it declares a function doing nothing,
+// so that the vars and packages only used by the routes of the profile are not linked into the binary.
+type ProfileRoutes struct {
+	Profile         string
+	Func            string
+	Params          []Param
+	Vars            Decls
+	Registrations   []Registration
+	stdImports      []string
+	usedImports     []string
+	stubStdImports  []string
+	stubUsedImports []string
+}
+
+var profileFileRegexp = regexp.MustCompile(`^profile_[A-Za-z0-9_]+_(enabled|disabled)\.go$`)
+
+func profileConst(profile string) string {
+	return "profile" + goName(profile)
+}
+
+// prepareProfiles validates the route profiles and sets the condition under which each
+// route is registered, e.g. profileDev || profileStaging.
+func prepareProfiles(cfg *Conf) error {
+	cfg.Profiles = []string{}
+	consts := map[string]string{}
+	for i := range cfg.Routes {
+		for j := range cfg.Routes[i].ParsedPaths {
+			path := &cfg.Routes[i].ParsedPaths[j]
+			conditions := []string{}
+			for _, profile := range path.Profiles {
+				if !isIdentifier(profile) {
+					return fmt.Errorf("invalid profile '%s' of route '%s' (%s), it should be a valid build tag made of letters, digits and underscores", profile, path.MuxPattern, path.Position)
+				}
+				name := profileConst(profile)
+				if other, exists := consts[name]; exists && other != profile {
+					return fmt.Errorf("profiles '%s' and '%s' would both be named %s", other, profile, name)
+				}
+				consts[name] = profile
+				if !slices.Contains(cfg.Profiles, profile) {
+					cfg.Profiles = append(cfg.Profiles, profile)
+				}
+				if !slices.Contains(conditions, name) {
+					conditions = append(conditions, name)
+				}
+			}
+			path.Condition = strings.Join(conditions, " || ")
+		}
+	}
+	sort.Strings(cfg.Profiles)
+	return nil
+}
+
+// prepareRegistrations splits the routes between ConfigureMux and the configure functions of their
+// profiles, declaring in each function the vars used by its routes. A route with several profiles
+// is registered by the function of the first enabled one.
+func prepareRegistrations(cfg *Conf) error {
+	cfg.routesFile.Registrations = []Registration{}
+	cfg.routesFile.ProfileRoutes = make([]ProfileRoutes, len(cfg.Profiles))
+	for i, profile := range cfg.Profiles {
+		cfg.routesFile.ProfileRoutes[i] = ProfileRoutes{Profile: profile, Func: "configure" + goName(profile) + "Routes", Registrations: []Registration{}}
+	}
+	for _, path := range allPaths(cfg) {
+		if len(path.Profiles) == 0 {
+			cfg.routesFile.Registrations = append(cfg.routesFile.Registrations, Registration{Router: cfg.Router, ReturnErrors: cfg.ReturnErrors, Path: path})
+			continue
+		}
+		guard := []string{}
+		for i, profile := range cfg.Profiles {
+			if !slices.Contains(path.Profiles, profile) {
+				continue
+			}
+			routes := &cfg.routesFile.ProfileRoutes[i]
+			routes.Registrations = append(routes.Registrations, Registration{Router: cfg.Router, ReturnErrors: cfg.ReturnErrors, Guard: strings.Join(guard, " && "), Path: path})
+			guard = append(guard, "!"+profileConst(profile))
+		}
+	}
+	var err error
+	if cfg.routesFile.ConfigureVars, _, err = scopeVars(cfg, cfg.routesFile.Registrations); err != nil {
+		return err
+	}
+	for i := range cfg.routesFile.ProfileRoutes {
+		routes := &cfg.routesFile.ProfileRoutes[i]
+		if routes.Vars, routes.Params, err = scopeVars(cfg, routes.Registrations); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scopeVars returns the vars referenced by the handlers and middlewares of the registered routes,
+// directly or through other vars, in declaration order, and the args referenced by them.
+func scopeVars(cfg *Conf, registrations []Registration) (Decls, []Param, error) {
+	used := map[string]bool{}
+	for _, registration := range registrations {
+		path := registration.Path
+		for _, expr := range append([]string{path.Handler}, path.Stack...) {
+			refs, err := identifiers(expr)
+			if err != nil {
+				return nil, nil, fmt.Errorf("error parsing expression '%s' of route '%s' (%s): %w", expr, path.MuxPattern, path.Position, err)
+			}
+			for _, ref := range refs {
+				used[ref] = true
+			}
+		}
+	}
+	// the ordered vars are declared after their dependencies, so walking them backwards
+	// marks the dependencies of a used var before reaching them
+	vars := Decls{}
+	for i := len(cfg.OrderedVars) - 1; i >= 0; i-- {
+		decl := cfg.OrderedVars[i]
+		if !used[decl.Name] {
+			continue
+		}
+		refs, err := identifiers(decl.Expr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing var '%s': %w", decl.Name, err)
+		}
+		for _, ref := range refs {
+			used[ref] = used[ref] || ref != decl.Name
+		}
+		vars = append(Decls{decl}, vars...)
+	}
+	params := []Param{}
+	for _, param := range cfg.routesFile.Params {
+		if used[param.Name] {
+			params = append(params, param)
+		}
+	}
+	return vars, params, nil
+}
+
+// prepareProfileImports resolves the imports of the profile files: the file built with the profile
+// build tag imports the packages of its routes and vars, the one built without it only the packages
+// of the mux and args types.
+func prepareProfileImports(cfg *Conf, basedir string) error {
+	for i := range cfg.routesFile.ProfileRoutes {
+		routes := &cfg.routesFile.ProfileRoutes[i]
+		types := []string{}
+		for _, param := range routes.Params {
+			types = append(types, param.Type)
+		}
+		refs, err := routeQualifiers(cfg, types, routes.Vars, routes.Registrations)
+		if err != nil {
+			return err
+		}
+		generated := []string{cfg.routesFile.RouterType}
+		if !cfg.ReturnErrors {
+			generated = append(generated, "route.Inject")
+		}
+		for _, registration := range routes.Registrations {
+			if registration.Path.builtin() {
+				generated = append(generated, registration.Path.Handler)
+			}
+			generated = append(generated, registration.Path.Validator)
+		}
+		if routes.stdImports, routes.usedImports, err = scopeImports(cfg, basedir, generated, refs); err != nil {
+			return err
+		}
+		if refs, err = routeQualifiers(cfg, types, nil, nil); err != nil {
+			return err
+		}
+		if routes.stubStdImports, routes.stubUsedImports, err = scopeImports(cfg, basedir, generated[:1], refs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generatedPackages are the packages referenced by the generated expressions of a profile file.
+var generatedPackages = map[string]string{
+	"http":     "net/http",
+	"route":    routePackage,
+	"static":   staticPackage,
+	"proxy":    proxyPackage,
+	"redirect": redirectPackage,
+	"chi":      chiPackage,
+}
+
+// scopeImports returns the standard library and other imports of the packages referenced by the
+// generated expressions and of the ones resolved from the qualifiers of the configured expressions.
+func scopeImports(cfg *Conf, basedir string, generated []string, refs map[string]string) ([]string, []string, error) {
+	present, imports := []string{}, []string{}
+	for _, expr := range generated {
+		found, err := qualifiers(expr)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, qualifier := range found {
+			if pkg, ok := generatedPackages[qualifier]; ok && !slices.Contains(present, pkg) {
+				present = append(present, pkg)
+				imports = append(imports, strconv.Quote(pkg))
+			}
+		}
+	}
+	resolved, err := resolveImports(cfg, basedir, refs, present)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, spec := range resolved {
+		// an explicit import of a generated package replaces it, e.g. an aliased chi import
+		imp, err := parseImport(spec)
+		if i := slices.Index(imports, strconv.Quote(imp.Path)); err == nil && i >= 0 {
+			imports[i] = spec
+		} else {
+			imports = append(imports, spec)
+		}
+	}
+	std, other := splitStdImports(imports)
+	return std, other, nil
+}
+
+// createProfileFiles writes, for every profile, the files declaring whether its routes are
+// registered and the function registering them: one built with the profile build tag and the
+// other one without it. Files of profiles no longer used are removed.
+func createProfileFiles(cfg *Conf, basedir string) error {
+	dir := path.Join(basedir, cfg.Out)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("error reading routes directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !profileFileRegexp.MatchString(entry.Name()) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil || !bytes.HasPrefix(data, []byte("// Code generated by muxc. DO NOT EDIT.")) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			return fmt.Errorf("error removing %s file: %w", entry.Name(), err)
+		}
+	}
+	for _, profile := range cfg.Profiles {
+		for _, enabled := range []bool{true, false} {
+			name := "profile_" + profile + "_disabled.go"
+			if enabled {
+				name = "profile_" + profile + "_enabled.go"
+			}
+			render := func(w io.Writer) error {
+				return cfg.RenderProfile(w, profile, enabled)
+			}
+			if err := writeFile(dir, name, render); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RenderProfile writes to w the file declaring whether the routes of a profile are registered,
+// built when the profile build tag is set if enabled is true, or when it is not set otherwise.
+func (cfg *Conf) RenderProfile(w io.Writer, profile string, enabled bool) error {
+	i := slices.IndexFunc(cfg.routesFile.ProfileRoutes, func(routes ProfileRoutes) bool { return routes.Profile == profile })
+	if i == -1 {
+		return fmt.Errorf("profile '%s' is not used by any route", profile)
+	}
+	file := ProfileFile{
+		ProfileRoutes: &cfg.routesFile.ProfileRoutes[i],
+		Package:       cfg.Package,
+		MuxcVersion:   cfg.MuxcVersion,
+		SourceFile:    cfg.SourceFile,
+		Const:         profileConst(profile),
+		Enabled:       enabled,
+		RouterType:    cfg.routesFile.RouterType,
+		ReturnErrors:  cfg.ReturnErrors,
+		StdImports:    cfg.routesFile.ProfileRoutes[i].stdImports,
+		UsedImports:   cfg.routesFile.ProfileRoutes[i].usedImports,
+	}
+	if !enabled {
+		file.StdImports, file.UsedImports = file.stubStdImports, file.stubUsedImports
+	}
+	return renderGo(w, "profile.go.tmpl", file)
+}
@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	RouterServeMux string = "servemux"
+	RouterChi      string = "chi"
+	RouterMethod   string = "method"
+)
+
+const chiPackage string = "github.com/go-chi/chi/v5"
+
+// routerTypes are the types of the mux parameter of the generated ConfigureMux for each router.
+var routerTypes = map[string]string{
+	RouterServeMux: "*http.ServeMux",
+	RouterChi:      "chi.Router",
+	RouterMethod:   "Router",
+}
+
+// prepareRouter translates the route patterns to the syntax of the configured router. Routes of
+// a chi router with wildcards set their chi url params as request path values before validating them.
+func prepareRouter(cfg *Conf) error {
+	if cfg.Router == "" {
+		cfg.Router = RouterServeMux
+	}
+	var ok bool
+	if cfg.routesFile.RouterType, ok = routerTypes[cfg.Router]; !ok {
+		return fmt.Errorf("invalid router '%s', it should be one of %s, %s or %s", cfg.Router, RouterServeMux, RouterChi, RouterMethod)
+	}
+	if cfg.Router == RouterChi {
+		cfg.addGenImport("net/url")
+	}
+	for i := range cfg.Routes {
+		for j := range cfg.Routes[i].ParsedPaths {
+			path := &cfg.Routes[i].ParsedPaths[j]
+			if cfg.Router == RouterServeMux {
+				path.RouterPattern = path.MuxPattern
+				continue
+			}
+			var err error
+			if path.RouterPattern, err = chiPattern(path.FullPattern); err != nil {
+				return fmt.Errorf("route '%s' (%s) is not supported by router %s: %w", path.MuxPattern, path.Position, cfg.Router, err)
+			}
+			if cfg.Router != RouterChi || len(path.Wildcards) == 0 {
+				continue
+			}
+			remainder := ""
+			for _, wc := range path.Wildcards {
+				if wc.Remainder {
+					remainder = wc.Name
+				}
+			}
+			if path.Validator == "" {
+				path.Validator = fmt.Sprintf("pathValues(%q)", remainder)
+			} else {
+				path.Validator = fmt.Sprintf("stack(%s, pathValues(%q))", path.Validator, remainder)
+			}
+		}
+	}
+	return nil
+}
+
+// chiPattern translates a pattern of http.ServeMux to the chi syntax: chi patterns match exactly,
+// so {$} is dropped and a trailing slash becomes a catch-all, as does a remainder wildcard.
+func chiPattern(pattern string) (string, error) {
+	if !strings.HasPrefix(pattern, "/") {
+		return "", fmt.Errorf("host patterns are not supported")
+	}
+	if strings.HasSuffix(pattern, "/{$}") {
+		return strings.TrimSuffix(pattern, "{$}"), nil
+	}
+	if strings.HasSuffix(pattern, "/") {
+		return pattern + "*", nil
+	}
+	if i := strings.LastIndex(pattern, "/"); strings.HasSuffix(pattern, "...}") {
+		return pattern[:i+1] + "*", nil
+	}
+	return pattern, nil
+}
+
+// addRouterImport adds the chi import used by the mux parameter of a chi router,
+// unless it is already imported.
+func addRouterImport(cfg *Conf) {
+	if cfg.Router != RouterChi {
+		return
+	}
+	for _, spec := range cfg.routesFile.UsedImports {
+		if imp, err := parseImport(spec); err == nil && imp.Path == chiPackage {
+			return
+		}
+	}
+	cfg.routesFile.UsedImports = append([]string{strconv.Quote(chiPackage)}, cfg.routesFile.UsedImports...)
+}
@@ -0,0 +1,253 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+type TestsConf struct {
+	Args Decls `yaml:"args"`
+}
+
+// TestsFile is the model of the generated routes_test.go file.
+type TestsFile struct {
+	Package      string
+	MuxcVersion  string
+	SourceFile   string
+	Imports      []string
+	Configure    string
+	ReturnErrors bool
+	Cases        []TestCase
+}
+
+// TestCase is a request that should be matched by the pattern of the route at Index.
+type TestCase struct {
+	Index  int
+	Method string
+	URL    string
+}
+
+// testsImports are the packages always imported by the generated tests.
+var testsImports = []string{"net/http", "net/http/httptest", "testing"}
+
+// testMethods are the methods tried for routes without method, as the method of a request to
+// such a route may be matched by another route with the same path.
+var testMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// regexpSample returns a short string matching a wildcard regexp, or an empty string if none is found.
+func regexpSample(expr string) string {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return ""
+	}
+	var sample func(re *syntax.Regexp) string
+	sample = func(re *syntax.Regexp) string {
+		switch re.Op {
+		case syntax.OpLiteral:
+			return string(re.Rune)
+		case syntax.OpCharClass:
+			for i := 0; i+1 < len(re.Rune); i += 2 {
+				for r := re.Rune[i]; r <= re.Rune[i+1] && r <= re.Rune[i]+128; r++ {
+					if r > ' ' && r != '/' && r != 0x7f {
+						return string(r)
+					}
+				}
+			}
+			return ""
+		case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+			return "a"
+		case syntax.OpCapture, syntax.OpPlus, syntax.OpAlternate:
+			return sample(re.Sub[0])
+		case syntax.OpRepeat:
+			return strings.Repeat(sample(re.Sub[0]), re.Min)
+		case syntax.OpConcat:
+			s := ""
+			for _, sub := range re.Sub {
+				s += sample(sub)
+			}
+			return s
+		}
+		return ""
+	}
+	s := sample(re)
+	if matched, _ := regexp.MatchString("^(?:"+expr+")$", s); !matched || strings.Contains(s, "/") {
+		return ""
+	}
+	return s
+}
+
+// testHosts are the hosts tried for patterns without host, as the first one may be matched by the
+// pattern of another route.
+var testHosts = []string{"localhost", "muxc.test"}
+
+// testURL returns the url of a pattern with the wildcards replaced by the given values, the
+// host of the url is the given one unless the pattern has a host.
+func testURL(pattern string, host string, values func(wc Wildcard) string) string {
+	url := &strings.Builder{}
+	url.WriteString("http://")
+	if strings.HasPrefix(pattern, "/") {
+		url.WriteString(host)
+	}
+	for len(pattern) > 0 {
+		start := strings.Index(pattern, "{")
+		if start == -1 {
+			url.WriteString(pattern)
+			break
+		}
+		url.WriteString(pattern[:start])
+		end := strings.Index(pattern, "}")
+		if name := pattern[start+1 : end]; name != "$" {
+			url.WriteString(values(Wildcard{Name: strings.TrimSuffix(name, "..."), Remainder: strings.HasSuffix(name, "...")}))
+		}
+		pattern = pattern[end+1:]
+	}
+	return url.String()
+}
+
+// testCase synthesises a request matched by the pattern of a path in a mux with every route,
+// first with wildcard values matching their types and then with values unlikely to match
+// the literal segments of other routes.
+func testCase(mux *http.ServeMux, path ParsedPath) (TestCase, bool) {
+	typed := map[string]Wildcard{}
+	for _, wc := range path.Wildcards {
+		typed[wc.Name] = wc
+	}
+	samples := []func(wc Wildcard) string{
+		func(wc Wildcard) string {
+			if wc.Remainder {
+				return "a/b"
+			}
+			wc = typed[wc.Name]
+			if wc.Type == WildcardRegexp {
+				if sample := regexpSample(wc.Regexp); sample != "" {
+					return sample
+				}
+			}
+			if sample := wildcardExample(wc); sample != "" {
+				return sample
+			}
+			return "1"
+		},
+		func(wc Wildcard) string {
+			return "muxc-" + wc.Name
+		},
+	}
+	methods := []string{path.Method}
+	if path.Method == "" {
+		methods = testMethods
+	}
+	for _, sample := range samples {
+		for _, host := range testHosts {
+			for _, method := range methods {
+				url := testURL(path.FullPattern, host, sample)
+				req, err := http.NewRequest(method, url, nil)
+				if err != nil {
+					continue
+				}
+				if _, pattern := mux.Handler(req); pattern == path.MuxPattern {
+					return TestCase{Index: path.Index, Method: method, URL: url}, true
+				}
+			}
+		}
+	}
+	return TestCase{}, false
+}
+
+func buildTestsFile(cfg *Conf, basedir string) (*TestsFile, []string, error) {
+	if cfg.Router != RouterServeMux {
+		return nil, nil, fmt.Errorf("tests are only generated for the %s router", RouterServeMux)
+	}
+	file := &TestsFile{
+		Package:      cfg.Package,
+		MuxcVersion:  cfg.MuxcVersion,
+		SourceFile:   cfg.SourceFile,
+		ReturnErrors: cfg.ReturnErrors,
+		Cases:        []TestCase{},
+	}
+	for _, decl := range cfg.Tests.Args {
+		if _, ok := cfg.Args.Lookup(decl.Name); !ok {
+			return nil, nil, fmt.Errorf("test arg '%s' is not declared in args", decl.Name)
+		}
+	}
+	refs := map[string]string{}
+	args := []string{}
+	for _, param := range cfg.routesFile.Params {
+		expr, ok := cfg.Tests.Args.Lookup(param.Name)
+		switch {
+		case cfg.ArgsStyle == ArgsPositional && !ok:
+			args = append(args, "*new("+param.Type+")")
+			expr = param.Type
+		case !ok:
+			continue
+		case cfg.ArgsStyle == ArgsOptions:
+			args = append(args, param.Field+": "+expr)
+		case cfg.ArgsStyle == ArgsFunctional:
+			args = append(args, "With"+param.Field+"("+expr+")")
+		default:
+			args = append(args, expr)
+		}
+		found, err := qualifiers(expr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing test arg '%s': %w", param.Name, err)
+		}
+		for _, qualifier := range found {
+			if _, exists := refs[qualifier]; !exists {
+				refs[qualifier] = expr
+			}
+		}
+	}
+	switch cfg.ArgsStyle {
+	case ArgsOptions:
+		file.Configure = "ConfigureMux(mux, Options{" + strings.Join(args, ", ") + "})"
+	default:
+		file.Configure = "ConfigureMux(" + strings.Join(append([]string{"mux"}, args...), ", ") + ")"
+	}
+	mux := http.NewServeMux()
+	for _, p := range allPaths(cfg) {
+		if err := registerPattern(mux, p.MuxPattern); err != nil {
+			return nil, nil, fmt.Errorf("error registering route '%s' (%s): %w", p.MuxPattern, p.Position, err)
+		}
+	}
+	warnings := []string{}
+	for _, p := range allPaths(cfg) {
+		test, ok := testCase(mux, *p)
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("unable to synthesise a url matched by route '%s' (%s), it is not tested", p.MuxPattern, p.Position))
+			continue
+		}
+		file.Cases = append(file.Cases, test)
+	}
+	imports, err := resolveImports(cfg, basedir, refs, testsImports)
+	if err != nil {
+		return nil, nil, err
+	}
+	file.Imports = imports
+	return file, warnings, nil
+}
+
+func registerPattern(mux *http.ServeMux, pattern string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	mux.Handle(pattern, http.NotFoundHandler())
+	return nil
+}
+
+// RenderTests writes the routes test file to w.
+func (cfg *Conf) RenderTests(w io.Writer) error {
+	if cfg.testsFile == nil {
+		return fmt.Errorf("tests are not configured")
+	}
+	return renderGo(w, "routes_test.go.tmpl", cfg.testsFile)
+}
+
+func createTestsFile(cfg *Conf, basedir string) error {
+	return writeFile(path.Join(basedir, cfg.Out), "routes_test.go", cfg.RenderTests)
+}
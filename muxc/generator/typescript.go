@@ -0,0 +1,154 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+type TypeScriptConf struct {
+	Out  string `yaml:"out"`
+	File string `yaml:"file"`
+}
+
+type TypeScriptFile struct {
+	MuxcVersion string
+	SourceFile  string
+	Functions   []TypeScriptFunction
+}
+
+type TypeScriptFunction struct {
+	Name   string
+	Method string
+	Params []ClientParam
+	Path   string
+	In     bool
+	Out    bool
+}
+
+var typeScriptReserved = []string{
+	"break", "case", "catch", "class", "const", "continue", "debugger", "default", "delete", "do", "else", "enum",
+	"export", "extends", "false", "finally", "for", "function", "if", "import", "in", "instanceof", "new", "null",
+	"return", "super", "switch", "this", "throw", "true", "try", "typeof", "var", "void", "while", "with", "let",
+	"static", "yield", "await", "implements", "interface", "package", "private", "protected", "public",
+	"arguments", "eval", "method", "body", "init", "request", "escapePath",
+}
+
+// typeScriptGlobals are the names declared by the generated module besides the route functions.
+var typeScriptGlobals = []string{"baseURL", "setBaseURL", "ResponseError", "escapePath", "request"}
+
+// lowerCamel converts a go name like ReadPet or URLFor into readPet or urlFor.
+func lowerCamel(name string) string {
+	runes := []rune(name)
+	for i := range runes {
+		if !unicode.IsUpper(runes[i]) {
+			break
+		}
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// typeScriptPath returns the template literal that builds the path of a route from its
+// wildcard parameters, the host part of the pattern (if any) is dropped.
+func typeScriptPath(pattern string, params map[string]ClientParam) string {
+	if i := strings.Index(pattern, "/"); i > 0 {
+		pattern = pattern[i:]
+	}
+	pattern = strings.TrimSuffix(pattern, "{$}")
+	escaper := strings.NewReplacer("\\", "\\\\", "`", "\\`", "${", "\\${")
+	literal := &strings.Builder{}
+	literal.WriteString("`")
+	for len(pattern) > 0 {
+		start := strings.Index(pattern, "{")
+		if start == -1 {
+			literal.WriteString(escaper.Replace(pattern))
+			break
+		}
+		literal.WriteString(escaper.Replace(pattern[:start]))
+		end := strings.Index(pattern, "}")
+		name := pattern[start+1 : end]
+		pattern = pattern[end+1:]
+		if strings.HasSuffix(name, "...") {
+			literal.WriteString("${escapePath(" + params[strings.TrimSuffix(name, "...")].Name + ")}")
+		} else {
+			literal.WriteString("${encodeURIComponent(String(" + params[name].Name + "))}")
+		}
+	}
+	literal.WriteString("`")
+	return literal.String()
+}
+
+func buildTypeScriptFile(cfg *Conf) (*TypeScriptFile, error) {
+	if err := checkRouteNames(cfg); err != nil {
+		return nil, err
+	}
+	file := &TypeScriptFile{
+		MuxcVersion: cfg.MuxcVersion,
+		SourceFile:  cfg.SourceFile,
+		Functions:   []TypeScriptFunction{},
+	}
+	declared := map[string]string{}
+	for i := range cfg.Routes {
+		for _, path := range cfg.Routes[i].ParsedPaths {
+			if path.builtin() {
+				continue
+			}
+			name := lowerCamel(path.RouteName())
+			if slices.Contains(typeScriptReserved, name) || slices.Contains(typeScriptGlobals, name) {
+				name += "Route"
+			}
+			if other, exists := declared[name]; exists {
+				return nil, fmt.Errorf("routes '%s' and '%s' both declare the typescript function %s, set a different name with the name field", other, path.FullPattern, name)
+			}
+			declared[name] = path.FullPattern
+			function := TypeScriptFunction{
+				Name:   name,
+				Method: path.Method,
+				Params: []ClientParam{},
+				In:     path.In != "",
+				Out:    path.Out != "",
+			}
+			params := map[string]ClientParam{}
+			for _, wc := range path.Wildcards {
+				param := ClientParam{Name: wc.Name, Type: "string"}
+				if slices.Contains(typeScriptReserved, param.Name) {
+					param.Name += "Param"
+				}
+				// int64 values may not be represented exactly by a number
+				if wc.GoType() == WildcardInt {
+					param.Type = "number"
+				}
+				params[wc.Name] = param
+				function.Params = append(function.Params, param)
+			}
+			function.Path = typeScriptPath(path.FullPattern, params)
+			file.Functions = append(file.Functions, function)
+		}
+	}
+	return file, nil
+}
+
+// RenderTypeScript writes the typescript client file to w.
+func (cfg *Conf) RenderTypeScript(w io.Writer) error {
+	if cfg.typeScriptFile == nil {
+		return fmt.Errorf("typescript client is not configured")
+	}
+	return templates.ExecuteTemplate(w, "api.ts.tmpl", cfg.typeScriptFile)
+}
+
+func createTypeScriptFile(cfg *Conf, basedir string) error {
+	if cfg.TypeScript.Out == "" {
+		return fmt.Errorf("typescript out directory is not configured")
+	}
+	if cfg.TypeScript.File == "" {
+		cfg.TypeScript.File = "api.ts"
+	}
+	return writeFile(path.Join(basedir, cfg.TypeScript.Out), cfg.TypeScript.File, cfg.RenderTypeScript)
+}
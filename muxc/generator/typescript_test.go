@@ -0,0 +1,78 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// parseTest parses a configuration with the given routes, importing the handlers package explicitly
+// so that no package has to be listed.
+func parseTest(t *testing.T, config string) (*Conf, error) {
+	t.Helper()
+	fsys := fstest.MapFS{"muxc.yaml": &fstest.MapFile{Data: []byte("package: routes\nout: .\nimports:\n  - example.com/handlers\n" + config)}}
+	yamlFile, err := Load(fsys, "muxc.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	yamlFile.BaseDir = t.TempDir()
+	cfg, _, err := Parse(yamlFile)
+	return cfg, err
+}
+
+func TestTypeScriptFunctions(t *testing.T) {
+	tests := []struct {
+		paths string
+		want  []string
+		err   string
+	}{
+		{
+			paths: "GET /pets ;handlers.ListPets\n      - GET /pets/{id:int64} ;handlers.ReadPet",
+			want:  []string{"export function listPets(init?: RequestInit)", "export function readPet(id: string, init?: RequestInit)"},
+		},
+		{
+			paths: "GET /pets/{id:int}/{name} ;handlers.ReadPet",
+			want:  []string{"export function readPet(id: number, name: string, init?: RequestInit)"},
+		},
+		// reserved words and the names declared by the module
+		{
+			paths: "DELETE /pets ;handlers.Delete\n      - PUT /pets ;handlers.New\n      - GET /default ;handlers.Default",
+			want:  []string{"export function deleteRoute(", "export function newRoute(", "export function defaultRoute("},
+		},
+		{
+			paths: "GET /a ;handlers.Request\n      - GET /b ;handlers.EscapePath\n      - GET /c ;handlers.SetBaseURL",
+			want:  []string{"export function requestRoute(", "export function escapePathRoute(", "export function setBaseURLRoute("},
+		},
+		{
+			paths: "GET /pets/{delete}/{init} ;handlers.ReadPet",
+			want:  []string{"export function readPet(deleteParam: string, initParam: string, init?: RequestInit)"},
+		},
+		{
+			paths: "GET /a ;handlers.Delete\n      - GET /b ;handlers.DeleteRoute",
+			err:   "routes '/a' and '/b' both declare the typescript function deleteRoute",
+		},
+	}
+	for _, test := range tests {
+		cfg, err := parseTest(t, "typescript:\n  out: .\nroutes:\n  - paths:\n      - "+test.paths+"\n")
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: got error %v, want %q", test.paths, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.paths, err)
+			continue
+		}
+		out := &bytes.Buffer{}
+		if err := cfg.RenderTypeScript(out); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range test.want {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("%s: %q not found in\n%s", test.paths, want, out)
+			}
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Decl struct {
+	Name string
+	Expr string
+}
+
+// Decls keeps the declaration order of a yaml mapping of names to go expressions (or
+// types in the case of args), a sequence of single entry mappings is also accepted.
+type Decls []Decl
+
+func (decls *Decls) UnmarshalYAML(node *yaml.Node) error {
+	if node.Tag == "!!null" {
+		return nil
+	}
+	entries := []*yaml.Node{node}
+	if node.Kind == yaml.SequenceNode {
+		entries = node.Content
+	}
+	for _, entry := range entries {
+		if entry.Kind != yaml.MappingNode {
+			return fmt.Errorf("line %d: expected a mapping of names to expressions", entry.Line)
+		}
+		for i := 0; i+1 < len(entry.Content); i += 2 {
+			name, expr := entry.Content[i], entry.Content[i+1]
+			if name.Kind != yaml.ScalarNode || expr.Kind != yaml.ScalarNode {
+				return fmt.Errorf("line %d: expected a name and an expression", name.Line)
+			}
+			if _, exists := decls.Lookup(name.Value); exists {
+				return fmt.Errorf("line %d: '%s' is declared more than once", name.Line, name.Value)
+			}
+			*decls = append(*decls, Decl{Name: name.Value, Expr: expr.Value})
+		}
+	}
+	return nil
+}
+
+func (decls Decls) Lookup(name string) (string, bool) {
+	for _, decl := range decls {
+		if decl.Name == name {
+			return decl.Expr, true
+		}
+	}
+	return "", false
+}
+
+func (decls Decls) Names() []string {
+	names := make([]string, len(decls))
+	for i := range decls {
+		names[i] = decls[i].Name
+	}
+	return names
+}
+
+// identifiers returns the names referenced by a go expression, ignoring selected
+// fields/methods (the Sel of x.Sel) and struct literal keys.
+func identifiers(expr string) ([]string, error) {
+	node, err := parser.ParseExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+	skip := map[*ast.Ident]bool{}
+	names := []string{}
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			skip[n.Sel] = true
+		case *ast.KeyValueExpr:
+			if key, ok := n.Key.(*ast.Ident); ok {
+				skip[key] = true
+			}
+		case *ast.Ident:
+			if !skip[n] && !slices.Contains(names, n.Name) {
+				names = append(names, n.Name)
+			}
+		}
+		return true
+	})
+	return names, nil
+}
+
+// orderVars sorts the vars so that every var is declared after the vars it references,
+// keeping the yaml order otherwise, and prunes the ones not used by any route as they
+// would not compile.
+func orderVars(cfg *Conf) ([]string, error) {
+	names := cfg.Vars.Names()
+	deps := map[string][]string{}
+	for _, decl := range cfg.Vars {
+		name := decl.Name
+		refs, err := identifiers(decl.Expr)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing var '%s': %w", name, err)
+		}
+		for _, ref := range refs {
+			// the var being declared is not in scope in its own declaration
+			if _, isVar := cfg.Vars.Lookup(ref); isVar && ref != name {
+				deps[name] = append(deps[name], ref)
+			}
+		}
+	}
+	if cycle := findCycle(names, deps); cycle != nil {
+		return nil, fmt.Errorf("vars form a dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+	used := map[string]bool{}
+	var use func(name string)
+	use = func(name string) {
+		if _, isVar := cfg.Vars.Lookup(name); !isVar || used[name] {
+			return
+		}
+		used[name] = true
+		for _, dep := range deps[name] {
+			use(dep)
+		}
+	}
+	for i := range cfg.Routes {
+		for _, path := range cfg.Routes[i].ParsedPaths {
+			for _, expr := range append([]string{path.Handler}, path.Stack...) {
+				refs, err := identifiers(expr)
+				if err != nil {
+					return nil, fmt.Errorf("error parsing expression '%s' of route '%s' (%s): %w", expr, path.MuxPattern, path.Position, err)
+				}
+				for _, ref := range refs {
+					use(ref)
+				}
+			}
+		}
+	}
+	warnings := []string{}
+	declared := map[string]bool{}
+	cfg.OrderedVars = Decls{}
+	for len(cfg.OrderedVars)+len(warnings) < len(names) {
+		for _, decl := range cfg.Vars {
+			name := decl.Name
+			if declared[name] || slices.ContainsFunc(deps[name], func(dep string) bool { return !declared[dep] }) {
+				continue
+			}
+			declared[name] = true
+			if used[name] {
+				cfg.OrderedVars = append(cfg.OrderedVars, decl)
+			} else {
+				warnings = append(warnings, fmt.Sprintf("var '%s' is not used by any route, it won't be declared", name))
+			}
+			break
+		}
+	}
+	return warnings, nil
+}
+
+func findCycle(names []string, deps map[string][]string) []string {
+	visiting, visited := map[string]bool{}, map[string]bool{}
+	var visit func(name string, stack []string) []string
+	visit = func(name string, stack []string) []string {
+		if visiting[name] {
+			return append(stack[slices.Index(stack, name):], name)
+		}
+		if visited[name] {
+			return nil
+		}
+		visiting[name] = true
+		for _, dep := range deps[name] {
+			if cycle := visit(dep, append(stack, name)); cycle != nil {
+				return cycle
+			}
+		}
+		visiting[name] = false
+		visited[name] = true
+		return nil
+	}
+	for _, name := range names {
+		if cycle := visit(name, []string{}); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
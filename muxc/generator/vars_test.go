@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestOrderVars(t *testing.T) {
+	tests := []struct {
+		name     string
+		vars     Decls
+		handler  string
+		stack    []string
+		want     []string
+		warnings []string
+		err      string
+	}{
+		{
+			name:    "yaml order",
+			vars:    Decls{{"a", `header("a")`}, {"b", `header("b")`}},
+			handler: "handlers.List",
+			stack:   []string{"b", "a"},
+			want:    []string{"a", "b"},
+		},
+		{
+			name:    "dependencies first",
+			vars:    Decls{{"json", "stack(content, accept)"}, {"content", `header("Content-Type")`}, {"accept", `header("Accept")`}},
+			handler: "handlers.List",
+			stack:   []string{"json"},
+			want:    []string{"content", "accept", "json"},
+		},
+		{
+			name:    "used by handlers and through other vars",
+			vars:    Decls{{"ctrl", "controllers.New(db)"}, {"db", "sql.Open()"}, {"unused", "handlers.Unused"}},
+			handler: "handlers.List(ctrl)",
+			want:    []string{"db", "ctrl"},
+			warnings: []string{
+				"var 'unused' is not used by any route, it won't be declared",
+			},
+		},
+		{
+			name:    "self reference is not a dependency",
+			vars:    Decls{{"logger", "logger.New()"}},
+			handler: "handlers.List",
+			stack:   []string{"logger"},
+			want:    []string{"logger"},
+		},
+		{
+			name:    "cycle",
+			vars:    Decls{{"a", "stack(b)"}, {"b", "stack(c)"}, {"c", "stack(a)"}},
+			handler: "handlers.List",
+			stack:   []string{"a"},
+			err:     "vars form a dependency cycle: a -> b -> c -> a",
+		},
+		{
+			name:    "invalid var",
+			vars:    Decls{{"a", "stack("}},
+			handler: "handlers.List",
+			err:     "error parsing var 'a'",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &Conf{
+				Vars:   test.vars,
+				Routes: []Routes{{ParsedPaths: []ParsedPath{{Handler: test.handler, Stack: test.stack}}}},
+			}
+			warnings, err := orderVars(cfg)
+			switch {
+			case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+				t.Errorf("got error %v, want %q", err, test.err)
+			case test.err == "" && err != nil:
+				t.Error(err)
+			case test.err == "" && !slices.Equal(cfg.OrderedVars.Names(), test.want):
+				t.Errorf("got vars %q, want %q", cfg.OrderedVars.Names(), test.want)
+			case test.err == "" && !slices.Equal(warnings, test.warnings):
+				t.Errorf("got warnings %q, want %q", warnings, test.warnings)
+			}
+		})
+	}
+}
+
+func TestFindCycle(t *testing.T) {
+	tests := []struct {
+		names []string
+		deps  map[string][]string
+		want  []string
+	}{
+		{names: []string{"a", "b"}, deps: map[string][]string{"a": {"b"}}, want: nil},
+		{names: []string{"a", "b", "c"}, deps: map[string][]string{"a": {"b", "c"}, "b": {"c"}}, want: nil},
+		{names: []string{"a"}, deps: map[string][]string{"a": {"a"}}, want: []string{"a", "a"}},
+		{names: []string{"a", "b"}, deps: map[string][]string{"a": {"b"}, "b": {"a"}}, want: []string{"a", "b", "a"}},
+		// the cycle is reported from its first var, not from the var reaching it
+		{names: []string{"a", "b", "c"}, deps: map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"b"}}, want: []string{"b", "c", "b"}},
+	}
+	for _, test := range tests {
+		if got := findCycle(test.names, test.deps); !slices.Equal(got, test.want) {
+			t.Errorf("%v: got cycle %q, want %q", test.deps, got, test.want)
+		}
+	}
+}
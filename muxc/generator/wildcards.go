@@ -0,0 +1,397 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	WildcardInt    = "int"
+	WildcardInt64  = "int64"
+	WildcardUUID   = "uuid"
+	WildcardSlug   = "slug"
+	WildcardRegexp = "regexp"
+)
+
+type Wildcard struct {
+	Name      string
+	Type      string
+	Regexp    string
+	Remainder bool
+	Segment   string // closest literal segment preceding the wildcard
+}
+
+func (wc Wildcard) Typed() bool {
+	return wc.Type != ""
+}
+
+func (wc Wildcard) GoType() string {
+	switch wc.Type {
+	case WildcardInt, WildcardInt64:
+		return wc.Type
+	default:
+		return "string"
+	}
+}
+
+// wildcardPatterns are the regexps validating the uuid and slug wildcards.
+var wildcardPatterns = map[string]string{
+	WildcardUUID: `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`,
+	WildcardSlug: `^[a-z0-9]+(?:-[a-z0-9]+)*$`,
+}
+
+// Pattern returns the anchored regexp validating the wildcard, or an empty string for untyped
+// and integer wildcards.
+func (wc Wildcard) Pattern() string {
+	if wc.Type == WildcardRegexp {
+		return "^(?:" + wc.Regexp + ")$"
+	}
+	return wildcardPatterns[wc.Type]
+}
+
+// Validator returns a function reporting whether a value is valid for the wildcard, as checked
+// by the generated code, or nil if the wildcard is untyped.
+func (wc Wildcard) Validator() (func(string) bool, error) {
+	switch wc.Type {
+	case "":
+		return nil, nil
+	case WildcardInt:
+		return func(s string) bool {
+			_, err := strconv.Atoi(s)
+			return err == nil
+		}, nil
+	case WildcardInt64:
+		return func(s string) bool {
+			_, err := strconv.ParseInt(s, 10, 64)
+			return err == nil
+		}, nil
+	}
+	re, err := regexp.Compile(wc.Pattern())
+	if err != nil {
+		return nil, fmt.Errorf("wildcard '%s' has an invalid regexp: %w", wc.Name, err)
+	}
+	return re.MatchString, nil
+}
+
+// parsePattern strips type annotations like {id:int64} from a pattern, returning
+// the pattern as it should be registered in the mux and the wildcards found in it.
+func parsePattern(pattern string) (string, []Wildcard, error) {
+	clean := &strings.Builder{}
+	wildcards := []Wildcard{}
+	segment, literal := "", ""
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '/':
+			if segment != "" {
+				literal = segment
+			}
+			segment = ""
+			clean.WriteByte('/')
+			continue
+		case '{':
+		default:
+			segment += string(pattern[i])
+			clean.WriteByte(pattern[i])
+			continue
+		}
+		end, depth := -1, 0
+		for j := i; j < len(pattern) && end == -1; j++ {
+			switch pattern[j] {
+			case '{':
+				depth++
+			case '}':
+				if depth--; depth == 0 {
+					end = j
+				}
+			}
+		}
+		if end == -1 {
+			return "", nil, fmt.Errorf("invalid pattern '%s', unclosed wildcard", pattern)
+		}
+		wc, err := parseWildcard(pattern[i+1 : end])
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid pattern '%s', %w", pattern, err)
+		}
+		if wc.Name == "$" {
+			clean.WriteString("{$}")
+		} else {
+			wc.Segment = literal
+			wildcards = append(wildcards, wc)
+			clean.WriteString("{" + wc.Name)
+			if wc.Remainder {
+				clean.WriteString("...")
+			}
+			clean.WriteString("}")
+		}
+		i = end
+	}
+	return clean.String(), wildcards, nil
+}
+
+func parseWildcard(def string) (wc Wildcard, err error) {
+	if def == "$" {
+		wc.Name = def
+		return
+	}
+	wc.Name, wc.Type, _ = strings.Cut(def, ":")
+	if wc.Type == "" {
+		if wc.Remainder = strings.HasSuffix(wc.Name, "..."); wc.Remainder {
+			wc.Name = strings.TrimSuffix(wc.Name, "...")
+		}
+	}
+	if !isIdentifier(wc.Name) {
+		err = fmt.Errorf("wildcard name '%s' is not a valid go identifier", wc.Name)
+		return
+	}
+	switch wc.Type {
+	case "", WildcardInt, WildcardInt64, WildcardUUID, WildcardSlug:
+	default:
+		if strings.HasSuffix(wc.Type, "...") {
+			err = fmt.Errorf("wildcard '%s' is a remainder, type annotations are not supported on remainder wildcards", wc.Name)
+			return
+		}
+		if _, err = regexp.Compile(wc.Type); err != nil {
+			err = fmt.Errorf("wildcard '%s' has an invalid regexp: %w", wc.Name, err)
+			return
+		}
+		wc.Regexp = wc.Type
+		wc.Type = WildcardRegexp
+	}
+	return
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+var initialisms = []string{"API", "HTML", "HTTP", "ID", "JSON", "SQL", "URI", "URL", "UUID", "XML"}
+
+// goName converts a path segment or wildcard name like "pet-owner" or "id" into
+// an exported go name like "PetOwner" or "ID".
+func goName(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	name := &strings.Builder{}
+	for _, word := range words {
+		upper := strings.ToUpper(word)
+		if slices.Contains(initialisms, upper) {
+			name.WriteString(upper)
+			continue
+		}
+		name.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return name.String()
+}
+
+type Accessor struct {
+	Name     string
+	Wildcard Wildcard
+}
+
+// accessors returns one typed accessor per distinct typed wildcard, named after the
+// literal segment that precedes it, e.g. /pet/{id:int64} produces PetIDFrom.
+func accessors(routes []Routes) ([]Accessor, error) {
+	result := []Accessor{}
+	for i := range routes {
+		for _, path := range routes[i].ParsedPaths {
+			for _, wc := range path.Wildcards {
+				if !wc.Typed() {
+					continue
+				}
+				accessor := Accessor{Name: goName(wc.Name) + "From", Wildcard: wc}
+				if !strings.HasPrefix(strings.ToLower(wc.Name), strings.ToLower(goName(wc.Segment))) {
+					accessor.Name = goName(wc.Segment) + accessor.Name
+				}
+				duplicated := false
+				for _, other := range result {
+					if other.Name != accessor.Name {
+						continue
+					}
+					if other.Wildcard.Name != wc.Name || other.Wildcard.Type != wc.Type || other.Wildcard.Regexp != wc.Regexp {
+						return nil, fmt.Errorf("wildcard '%s' in path '%s' conflicts with another wildcard producing accessor %s", wc.Name, path.Pattern, accessor.Name)
+					}
+					duplicated = true
+				}
+				if !duplicated {
+					result = append(result, accessor)
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// prepareWildcards builds the validation middleware of each path with typed wildcards,
+// the typed accessors and the imports the generated helpers need.
+func prepareWildcards(cfg *Conf) (err error) {
+	for i := range cfg.Routes {
+		for j := range cfg.Routes[i].ParsedPaths {
+			path := &cfg.Routes[i].ParsedPaths[j]
+			checks := []string{}
+			for k, wc := range path.Wildcards {
+				for _, other := range path.Wildcards[:k] {
+					if other.Name == wc.Name {
+						return fmt.Errorf("wildcard '%s' is duplicated in path '%s%s'", wc.Name, cfg.Routes[i].Base, path.Pattern)
+					}
+				}
+				if wc.Typed() {
+					checks = append(checks, fmt.Sprintf("wildcard{%q, %s}", wc.Name, cfg.wildcardCheck(wc)))
+				}
+			}
+			if len(checks) > 0 {
+				path.Validator = "validate(" + strings.Join(checks, ", ") + ")"
+			}
+		}
+	}
+	if cfg.routesFile.Accessors, err = accessors(cfg.Routes); err != nil {
+		return
+	}
+	cfg.routesFile.GenImports = []string{"net/http"}
+	if cfg.ReturnErrors {
+		cfg.addGenImport("fmt")
+	}
+	if len(cfg.routesFile.Accessors) > 0 {
+		cfg.addGenImport("regexp")
+		cfg.addGenImport("strconv")
+	}
+	if cfg.routesFile.URLBuilders, err = urlBuilders(cfg); err != nil {
+		return
+	}
+	return nil
+}
+
+func (cfg *Conf) wildcardCheck(wc Wildcard) string {
+	switch wc.Type {
+	case WildcardInt:
+		return "isInt"
+	case WildcardInt64:
+		return "isInt64"
+	case WildcardUUID:
+		return "uuidRegexp.MatchString"
+	case WildcardSlug:
+		return "slugRegexp.MatchString"
+	}
+	if !slices.Contains(cfg.routesFile.Regexps, wc.Pattern()) {
+		cfg.routesFile.Regexps = append(cfg.routesFile.Regexps, wc.Pattern())
+	}
+	return fmt.Sprintf("wildcardRegexp%d.MatchString", slices.Index(cfg.routesFile.Regexps, wc.Pattern()))
+}
+
+func (cfg *Conf) addGenImport(pkg string) {
+	if !slices.Contains(cfg.routesFile.GenImports, pkg) && !slices.Contains(cfg.Imports, pkg) {
+		cfg.routesFile.GenImports = append(cfg.routesFile.GenImports, pkg)
+	}
+}
+
+func lastLiteral(pattern string) string {
+	segments := strings.Split(pattern, "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if segments[i] != "" && !strings.HasPrefix(segments[i], "{") {
+			return segments[i]
+		}
+	}
+	return ""
+}
+
+// pathExpr returns the go expression that builds the path of a route from its
+// wildcard parameters, the host part of the pattern (if any) is dropped.
+func pathExpr(pattern string, params map[string]ClientParam) string {
+	if i := strings.Index(pattern, "/"); i > 0 {
+		pattern = pattern[i:]
+	}
+	pattern = strings.TrimSuffix(pattern, "{$}")
+	parts := []string{}
+	literal := ""
+	for len(pattern) > 0 {
+		start := strings.Index(pattern, "{")
+		if start == -1 {
+			literal += pattern
+			break
+		}
+		literal += pattern[:start]
+		end := strings.Index(pattern, "}")
+		name := pattern[start+1 : end]
+		pattern = pattern[end+1:]
+		if literal != "" {
+			parts = append(parts, strconv.Quote(literal))
+			literal = ""
+		}
+		if strings.HasSuffix(name, "...") {
+			parts = append(parts, "escapePath("+params[strings.TrimSuffix(name, "...")].Name+")")
+			continue
+		}
+		param := params[name]
+		switch param.Type {
+		case WildcardInt:
+			parts = append(parts, "strconv.Itoa("+param.Name+")")
+		case WildcardInt64:
+			parts = append(parts, "strconv.FormatInt("+param.Name+", 10)")
+		default:
+			parts = append(parts, "url.PathEscape("+param.Name+")")
+		}
+	}
+	if literal != "" {
+		parts = append(parts, strconv.Quote(literal))
+	}
+	if len(parts) == 0 {
+		return `"/"`
+	}
+	return strings.Join(parts, " + ")
+}
+
+type URLBuilder struct {
+	Name    string
+	Pattern string
+	Params  []ClientParam
+	Path    string
+}
+
+// urlBuilders returns a reverse url builder for every explicitly named route.
+func urlBuilders(cfg *Conf) ([]URLBuilder, error) {
+	builders := []URLBuilder{}
+	names := map[string]string{}
+	for i := range cfg.Routes {
+		for _, path := range cfg.Routes[i].ParsedPaths {
+			if path.Name == "" {
+				continue
+			}
+			if !isIdentifier(path.Name) {
+				return nil, fmt.Errorf("route name '%s' (%s) is not a valid go identifier", path.Name, path.Position)
+			}
+			if other, exists := names[path.Name]; exists {
+				return nil, fmt.Errorf("route name '%s' is used by routes at %s and %s", path.Name, other, path.Position)
+			}
+			names[path.Name] = path.Position.String()
+			builder := URLBuilder{Name: goName(path.Name), Pattern: path.FullPattern, Params: []ClientParam{}}
+			params := map[string]ClientParam{}
+			for _, wc := range path.Wildcards {
+				param := ClientParam{Name: clientParamName(wc.Name), Type: wc.GoType()}
+				params[wc.Name] = param
+				builder.Params = append(builder.Params, param)
+				if param.Type != "string" {
+					cfg.addGenImport("strconv")
+				}
+			}
+			builder.Path = pathExpr(path.FullPattern, params)
+			builders = append(builders, builder)
+		}
+	}
+	if len(builders) > 0 {
+		cfg.addGenImport("net/url")
+		cfg.addGenImport("strings")
+	}
+	return builders, nil
+}
@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestURLBuilders(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths string
+		want  []URLBuilder
+		err   string
+	}{
+		{
+			name:  "only named routes",
+			paths: "      - GET /pets ;handlers.List\n      - path: GET /pets/{id:int64} ;handlers.Read\n        name: readPet\n",
+			want: []URLBuilder{{
+				Name:    "ReadPet",
+				Pattern: "/api/pets/{id}",
+				Params:  []ClientParam{{Name: "id", Type: "int64"}},
+				Path:    `"/api/pets/" + strconv.FormatInt(id, 10)`,
+			}},
+		},
+		{
+			name:  "typed, escaped and remainder wildcards",
+			paths: "      - path: GET /users/{n:int}/{type}/{rest...} ;handlers.File\n        name: File\n",
+			want: []URLBuilder{{
+				Name:    "File",
+				Pattern: "/api/users/{n}/{type}/{rest...}",
+				Params:  []ClientParam{{Name: "n", Type: "int"}, {Name: "typeParam", Type: "string"}, {Name: "rest", Type: "string"}},
+				Path:    `"/api/users/" + strconv.Itoa(n) + "/" + url.PathEscape(typeParam) + "/" + escapePath(rest)`,
+			}},
+		},
+		{
+			name:  "exact path",
+			paths: "      - path: GET /{$} ;handlers.Index\n        name: Index\n",
+			want:  []URLBuilder{{Name: "Index", Pattern: "/api/{$}", Params: []ClientParam{}, Path: `"/api/"`}},
+		},
+		{
+			name:  "invalid name",
+			paths: "      - path: GET /pets ;handlers.List\n        name: list-pets\n",
+			err:   "route name 'list-pets' (muxc.yaml:8) is not a valid go identifier",
+		},
+		{
+			name:  "duplicated name",
+			paths: "      - path: GET /pets ;handlers.List\n        name: List\n      - path: GET /animals ;handlers.List\n        name: List\n",
+			err:   "route name 'List' is used by routes at muxc.yaml:8 and muxc.yaml:10",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg, err := parseTest(t, "routes:\n  - base: /api\n    paths:\n"+test.paths)
+			switch {
+			case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+				t.Errorf("got error %v, want %q", err, test.err)
+			case test.err == "" && err != nil:
+				t.Error(err)
+			case test.err == "" && !reflect.DeepEqual(cfg.routesFile.URLBuilders, test.want):
+				t.Errorf("got builders %+v, want %+v", cfg.routesFile.URLBuilders, test.want)
+			}
+		})
+	}
+}
@@ -2,4 +2,12 @@ module github.com/enolgor/muxc/muxc
 
 go 1.23.3
 
-require gopkg.in/yaml.v3 v3.0.1 // indirect
+require (
+	golang.org/x/tools v0.28.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	golang.org/x/mod v0.22.0 // indirect
+	golang.org/x/sync v0.10.0 // indirect
+)
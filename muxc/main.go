@@ -1,27 +1,57 @@
 package main
 
 import (
+	"bytes"
 	"crypto/md5"
 	"encoding/hex"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
+	"io/fs"
+	"net/http"
 	"os"
+	"os/exec"
+	"path"
 	"path/filepath"
+	"strings"
 	"time"
+
+	"github.com/enolgor/muxc/muxc/generator"
+	"github.com/enolgor/muxc/muxc/mock"
 )
 
 var file string
 var watch bool
+var printIR bool
 
 func init() {
 	flag.StringVar(&file, "f", "muxc.yaml", "path to yaml configuration file")
 	flag.BoolVar(&watch, "w", false, "watch and rebuild changes to configuration file")
-	flag.Parse()
+	flag.BoolVar(&printIR, "ir", false, "print the json intermediate representation passed to plugins instead of generating")
 }
 
 func main() {
+	flag.Parse()
 	var run func() error
+	if flag.Arg(0) == "diff" {
+		breaking, err := diffFiles(flag.Args()[1:], os.Stdin, os.Stdout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+			os.Exit(-1)
+		}
+		if breaking {
+			os.Exit(1)
+		}
+		return
+	}
+	if flag.Arg(0) == "mock" {
+		if err := serveMock(flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+			os.Exit(-1)
+		}
+		return
+	}
 	if watch {
 		run = watchAndRebuild
 	} else {
@@ -40,16 +70,174 @@ func processFile() error {
 	}
 	defer f.Close()
 	dir := filepath.Dir(file)
-	yamlfile, err := NewMultiYamlFile(file, f, dir)
+	yamlfile, err := generator.NewMultiYamlFile(file, f, dir)
 	if err != nil {
 		return fmt.Errorf("error merging yaml files: %s", err.Error())
 	}
-	if err = Generate(yamlfile); err != nil {
+	if printIR {
+		return writeIR(yamlfile, os.Stdout)
+	}
+	warnings, err := generator.Generate(yamlfile)
+	if err != nil {
 		return fmt.Errorf("error generating muxc routes: %s", err.Error())
 	}
+	printWarnings(warnings, os.Stderr)
 	return nil
 }
 
+func writeIR(yamlfile *generator.MultiYamlFile, w io.Writer) error {
+	cfg, warnings, err := generator.Parse(yamlfile)
+	if err != nil {
+		return fmt.Errorf("error parsing muxc routes: %s", err.Error())
+	}
+	printWarnings(warnings, os.Stderr)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(cfg.IR())
+}
+
+// diffFiles prints the route changes between two yaml configuration files, reporting whether any
+// of them is breaking. The old file is read from stdin if its name is -, or from a git revision
+// if it is written as <ref>:<path> (as with git show), along with its includes.
+func diffFiles(args []string, stdin io.Reader, w io.Writer) (bool, error) {
+	if len(args) != 2 {
+		return false, fmt.Errorf("usage: muxc diff <old.yaml|<ref>:<old.yaml>|-> <new.yaml>")
+	}
+	oldName, newName := args[0], args[1]
+	var oldFile *generator.MultiYamlFile
+	var err error
+	if oldName == "-" {
+		data, readErr := io.ReadAll(stdin)
+		if readErr != nil {
+			return false, fmt.Errorf("error reading stdin: %s", readErr.Error())
+		}
+		name := path.Base(filepath.ToSlash(newName))
+		oldFile, err = generator.Load(revisionFS{stdinName: name, stdin: data}, name)
+	} else if ref, name, ok := strings.Cut(oldName, ":"); ok && ref != "" && !fileExists(oldName) {
+		rfs := revisionFS{ref: ref}
+		if strings.HasPrefix(name, "./") {
+			rfs.prefix = "./"
+		}
+		oldFile, err = generator.Load(rfs, path.Clean(name))
+	} else {
+		oldFile, err = generator.LoadFile(oldName)
+	}
+	if err != nil {
+		return false, fmt.Errorf("error merging yaml files of %s: %s", oldName, err.Error())
+	}
+	newFile, err := generator.LoadFile(newName)
+	if err != nil {
+		return false, fmt.Errorf("error merging yaml files of %s: %s", newName, err.Error())
+	}
+	oldCfg, err := parseRoutes(oldFile)
+	if err != nil {
+		return false, err
+	}
+	newCfg, err := parseRoutes(newFile)
+	if err != nil {
+		return false, err
+	}
+	breaking := false
+	for _, change := range generator.Diff(oldCfg, newCfg) {
+		fmt.Fprintln(w, change)
+		breaking = breaking || change.Breaking
+	}
+	return breaking, nil
+}
+
+func fileExists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
+
+// revisionFS reads the files of the old configuration of a diff from a git revision, or only the
+// configuration itself when it is read from stdin, as its includes would be read from the working
+// tree instead of the revision it comes from.
+type revisionFS struct {
+	ref       string
+	prefix    string
+	stdinName string
+	stdin     []byte
+}
+
+func (rfs revisionFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if rfs.ref == "" {
+		if name != rfs.stdinName {
+			return nil, fmt.Errorf("'%s' can't be included by a configuration read from stdin, pass it as <ref>:<path> instead (e.g. main:muxc.yaml)", name)
+		}
+		return newMemFile(name, rfs.stdin), nil
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", "show", rfs.ref+":"+rfs.prefix+name)
+	cmd.Stderr = &stderr
+	data, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s:%s%s: %s", rfs.ref, rfs.prefix, name, strings.TrimSpace(stderr.String()))
+	}
+	return newMemFile(name, data), nil
+}
+
+// memFile is a read only fs.File holding the content of a file.
+type memFile struct {
+	*bytes.Reader
+	info memFileInfo
+}
+
+func newMemFile(name string, data []byte) *memFile {
+	return &memFile{Reader: bytes.NewReader(data), info: memFileInfo{name: path.Base(name), size: int64(len(data))}}
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memFile) Close() error               { return nil }
+
+type memFileInfo struct {
+	name string
+	size int64
+}
+
+func (info memFileInfo) Name() string       { return info.name }
+func (info memFileInfo) Size() int64        { return info.size }
+func (info memFileInfo) Mode() fs.FileMode  { return 0o444 }
+func (info memFileInfo) ModTime() time.Time { return time.Time{} }
+func (info memFileInfo) IsDir() bool        { return false }
+func (info memFileInfo) Sys() any           { return nil }
+
+// serveMock serves the example responses of the routes of the yaml configuration file.
+func serveMock(args []string) error {
+	flags := flag.NewFlagSet("mock", flag.ExitOnError)
+	addr := flags.String("addr", ":8080", "address to listen on")
+	latency := flags.Duration("latency", 0, "latency added to every response")
+	errorRate := flags.Float64("error-rate", 0, "probability (from 0 to 1) of responding with an internal server error")
+	flags.Parse(args)
+	yamlfile, err := generator.LoadFile(file)
+	if err != nil {
+		return fmt.Errorf("error merging yaml files: %s", err.Error())
+	}
+	handler, err := mock.New(yamlfile, mock.Options{Latency: *latency, ErrorRate: *errorRate})
+	if err != nil {
+		return fmt.Errorf("error creating mock server: %s", err.Error())
+	}
+	fmt.Printf("serving mock responses on %s...\n", *addr)
+	return http.ListenAndServe(*addr, handler)
+}
+
+func parseRoutes(yamlfile *generator.MultiYamlFile) (*generator.Conf, error) {
+	cfg, _, err := generator.ParseRoutes(yamlfile)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing muxc routes: %s", err.Error())
+	}
+	return cfg, nil
+}
+
+func printWarnings(warnings []string, w io.Writer) {
+	for i := range warnings {
+		fmt.Fprintf(w, "warning: %s\n", warnings[i])
+	}
+}
+
 func isSameErr(err1, err2 error) bool {
 	if err1 == nil && err2 == nil {
 		return true
@@ -77,7 +265,7 @@ func watchAndRebuild() error {
 			lastErr = printErrIfNotSame(lastErr, err, os.Stderr, "unable to locate %s file: %s\n", file, err.Error())
 			continue
 		}
-		yamlfile, err := NewMultiYamlFile(file, f, filepath.Dir(file))
+		yamlfile, err := generator.NewMultiYamlFile(file, f, filepath.Dir(file))
 		if err != nil {
 			lastErr = printErrIfNotSame(lastErr, err, os.Stderr, "error merging yaml files: %s\n", err.Error())
 			continue
@@ -89,10 +277,11 @@ func watchAndRebuild() error {
 		if checksum != lastChecksum {
 			lastChecksum = checksum
 			now := time.Now()
-			if err = Generate(yamlfile); err != nil {
+			if warnings, err := generator.Generate(yamlfile); err != nil {
 				lastErr = printErrIfNotSame(lastErr, err, os.Stderr, "error generating muxc routes: %s\n", err.Error())
 				continue
 			} else {
+				printWarnings(warnings, os.Stderr)
 				fmt.Printf("Built changes in %s\n", time.Since(now))
 			}
 		}
@@ -100,7 +289,7 @@ func watchAndRebuild() error {
 	}
 }
 
-func getFileChecksums(yamlfile *MultiYamlFile) (string, error) {
+func getFileChecksums(yamlfile *generator.MultiYamlFile) (string, error) {
 	multihash := ""
 	paths := yamlfile.GetAllFilePaths()
 	for i := range paths {
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	dir := t.TempDir()
+	write := func(name string, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=muxc", "-c", "user.email=muxc@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %s: %s", strings.Join(args, " "), err, out)
+		}
+	}
+	const header = "package: routes\nout: .\n"
+	const oldRoutes = "routes:\n  - paths:\n      - GET /pets ;handlers.List\n      - GET /pets/{id} ;handlers.Read\n"
+	const newRoutes = "routes:\n  - paths:\n      - GET /pets ;handlers.List\n      - GET /animals/{id} ;handlers.Read\n"
+	write("muxc.yaml", header+"!include routes.yaml\n")
+	write("routes.yaml", oldRoutes)
+	write("single.yaml", header+oldRoutes)
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "routes")
+	write("routes.yaml", newRoutes)
+	write("single.yaml", header+newRoutes)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	changed := "breaking: changed GET /pets/{id} (routes.yaml:4): pattern '/pets/{id}' -> '/animals/{id}'\n"
+	tests := []struct {
+		args  []string
+		stdin string
+		want  string
+		err   string
+	}{
+		{args: []string{"muxc.yaml", "muxc.yaml"}, want: ""},
+		// the includes of a revision are read from the revision
+		{args: []string{"HEAD:muxc.yaml", "muxc.yaml"}, want: changed},
+		{args: []string{"HEAD:./muxc.yaml", "muxc.yaml"}, want: changed},
+		{args: []string{"HEAD:missing.yaml", "muxc.yaml"}, err: "error reading HEAD:missing.yaml"},
+		// stdin can't include files, they would be read from the working tree
+		{args: []string{"-", "single.yaml"}, stdin: header + oldRoutes, want: strings.Replace(changed, "routes.yaml:4", "single.yaml:6", 1)},
+		{args: []string{"-", "muxc.yaml"}, stdin: header + "!include routes.yaml\n", err: "'routes.yaml' can't be included by a configuration read from stdin"},
+		{args: []string{"muxc.yaml"}, err: "usage: muxc diff"},
+	}
+	for _, test := range tests {
+		out := &bytes.Buffer{}
+		breaking, err := diffFiles(test.args, strings.NewReader(test.stdin), out)
+		switch {
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%q: got error %v, want %q", test.args, err, test.err)
+		case test.err == "" && err != nil:
+			t.Errorf("%q: %v", test.args, err)
+		case test.err == "" && (out.String() != test.want || breaking != (test.want != "")):
+			t.Errorf("%q: got %q (breaking %t), want %q", test.args, out.String(), breaking, test.want)
+		}
+	}
+}
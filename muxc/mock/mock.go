@@ -0,0 +1,105 @@
+// Package mock serves the example responses of the routes of a muxc yaml configuration, so that
+// clients can be developed before the handlers exist. Routes are registered with the same
+// patterns as in generated code, regardless of their profiles, and routes without examples
+// respond with 501 Not Implemented. Static, proxy and redirect routes are skipped, as by the
+// generated mock handlers.
+//
+// The first example of a route is served by default, requests can select another one with
+// the Prefer header, either by name (Prefer: example=notFound) or by status (Prefer: code=404).
+// The first example or code preference of the header decides, as in the generated mock handlers,
+// and 404 Not Found ("no example response matching the Prefer header") is returned if no example
+// matches it.
+package mock
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/enolgor/muxc/muxc/generator"
+)
+
+type Options struct {
+	// Latency is added to every response.
+	Latency time.Duration
+	// ErrorRate is the probability (from 0 to 1) of responding with 500 Internal Server Error
+	// instead of the example response.
+	ErrorRate float64
+}
+
+// New returns a handler serving the example responses of the routes of a yaml configuration,
+// static, proxy and redirect routes are not registered.
+func New(yamlFile *generator.MultiYamlFile, opts Options) (http.Handler, error) {
+	if opts.ErrorRate < 0 || opts.ErrorRate > 1 {
+		return nil, fmt.Errorf("invalid error rate %v, it should be between 0 and 1", opts.ErrorRate)
+	}
+	cfg, _, err := generator.ParseRoutes(yamlFile)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	for i := range cfg.Routes {
+		for _, path := range cfg.Routes[i].ParsedPaths {
+			if path.Static != nil || path.Proxy != nil || path.Redirect != nil {
+				continue
+			}
+			mux.Handle(path.MuxPattern, Handler(path.Examples))
+		}
+	}
+	if opts.Latency == 0 && opts.ErrorRate == 0 {
+		return mux, nil
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		time.Sleep(opts.Latency)
+		if opts.ErrorRate > 0 && rand.Float64() < opts.ErrorRate {
+			http.Error(w, "mock error", http.StatusInternalServerError)
+			return
+		}
+		mux.ServeHTTP(w, req)
+	}), nil
+}
+
+// Handler serves the example responses of a route, responding with 501 Not Implemented if it has
+// none and with 404 Not Found if none matches the Prefer header of the request.
+func Handler(examples []generator.ExampleResponse) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if len(examples) == 0 {
+			http.Error(w, "no example response for "+req.Pattern, http.StatusNotImplemented)
+			return
+		}
+		example, ok := Select(examples, req.Header.Values("Prefer"))
+		if !ok {
+			http.Error(w, "no example response matching the Prefer header", http.StatusNotFound)
+			return
+		}
+		for _, header := range example.Headers {
+			w.Header().Add(header.Name, header.Value)
+		}
+		w.WriteHeader(example.Status)
+		w.Write([]byte(example.Body))
+	}
+}
+
+// Select returns the example preferred by the values of a Prefer header, the first example or
+// code preference decides, or the first example if they don't express a preference.
+func Select(examples []generator.ExampleResponse, prefer []string) (generator.ExampleResponse, bool) {
+	for _, value := range prefer {
+		for _, preference := range strings.Split(value, ",") {
+			key, value, _ := strings.Cut(preference, "=")
+			key, value = strings.TrimSpace(key), strings.Trim(strings.TrimSpace(value), `"`)
+			if key != "example" && key != "code" {
+				continue
+			}
+			for _, example := range examples {
+				if key == "example" && example.Name == value || key == "code" && strconv.Itoa(example.Status) == value {
+					return example, true
+				}
+			}
+			return generator.ExampleResponse{}, false
+		}
+	}
+	return examples[0], true
+}
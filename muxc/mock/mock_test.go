@@ -0,0 +1,61 @@
+package mock
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/enolgor/muxc/muxc/generator"
+)
+
+func TestNew(t *testing.T) {
+	fsys := fstest.MapFS{"muxc.yaml": {Data: []byte(`package: routes
+out: .
+routes:
+  - paths:
+      - path: GET /pets/{id} ;handlers.ReadPet
+        examples:
+          - body: Rex
+          - name: notFound
+            status: 404
+            body: pet not found
+      - GET /users ;handlers.ListUsers
+      - path: GET /old
+        redirect:
+          to: /pets/1
+`)}}
+	yamlFile, err := generator.Load(fsys, "muxc.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler, err := New(yamlFile, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path   string
+		prefer string
+		status int
+		body   string
+	}{
+		{path: "/pets/1", status: http.StatusOK, body: "Rex"},
+		{path: "/pets/1", prefer: "example=notFound", status: http.StatusNotFound, body: "pet not found"},
+		{path: "/pets/1", prefer: "code=404", status: http.StatusNotFound, body: "pet not found"},
+		{path: "/pets/1", prefer: "code=500", status: http.StatusNotFound, body: "no example response matching the Prefer header\n"},
+		{path: "/users", status: http.StatusNotImplemented, body: "no example response for GET /users\n"},
+		// builtin routes are not registered
+		{path: "/old", status: http.StatusNotFound, body: "404 page not found\n"},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, test.path, nil)
+		if test.prefer != "" {
+			req.Header.Set("Prefer", test.prefer)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != test.status || rec.Body.String() != test.body {
+			t.Errorf("%s (%s): got %d %q, want %d %q", test.path, test.prefer, rec.Code, rec.Body.String(), test.status, test.body)
+		}
+	}
+}